@@ -45,7 +45,7 @@ func (c *Context) Accounts(ctx context.Context, opts *options.QueryOptions) (res
 		resp   authtypes.QueryAccountsResponse
 		method = "/cosmos.auth.v1beta1.Query/Accounts"
 		req    = &authtypes.QueryAccountsRequest{
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
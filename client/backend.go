@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client/options"
+)
+
+// QueryBackend performs the gRPC queries of a Context, such as QueryGRPC and the typed queries built on it,
+// in place of the ABCI queries sent to the RPC address of the query options.
+type QueryBackend interface {
+	// Endpoint returns the address the queries are sent to, reported to the query interceptors.
+	Endpoint() string
+
+	// Query performs the query of the given gRPC method with the marshalled request, and returns the
+	// marshalled response as the value of the ABCI response, along with the height it was answered at.
+	// It returns an error wrapping sentinelsdk.ErrNotFound if the queried item does not exist.
+	Query(ctx context.Context, method string, data []byte, opts *options.QueryOptions) (*abcitypes.ResponseQuery, error)
+}
+
+// TxBackend broadcasts the transactions of a Context in place of the RPC address of the transaction options.
+type TxBackend interface {
+	// Endpoint returns the address the transactions are broadcast to, reported to the transaction interceptors.
+	Endpoint() string
+
+	// BroadcastTx broadcasts the signed and encoded transaction with the given broadcast mode,
+	// either "sync" or "async".
+	BroadcastTx(ctx context.Context, txBytes []byte, mode string) (*coretypes.ResultBroadcastTx, error)
+}
+
+// WithQueryBackend sets the backend performing the gRPC queries of the Context and returns the modified instance.
+// A nil backend restores the default, which performs the queries with ABCI.
+func (c *Context) WithQueryBackend(v QueryBackend) *Context {
+	c.queryBackend = v
+	return c
+}
+
+// WithTxBackend sets the backend broadcasting the transactions of the Context and returns the modified instance.
+// A nil backend restores the default, which broadcasts the transactions to the RPC address of the options.
+func (c *Context) WithTxBackend(v TxBackend) *Context {
+	c.txBackend = v
+	return c
+}
+
+// queryBackendWithOptions performs a gRPC query with the backend of the Context, through the query interceptors.
+// The options are merged over the default query options of the Context, and may be nil.
+func (c *Context) queryBackendWithOptions(ctx context.Context, method string, data []byte, opts *options.QueryOptions) (*abcitypes.ResponseQuery, error) {
+	// Merge the provided options over the default options.
+	opts = c.QueryOptions(opts)
+
+	// Describe the query for the interceptors.
+	info := &QueryInfo{
+		Endpoint: c.queryBackend.Endpoint(),
+		Height:   opts.Height,
+		Path:     method,
+	}
+
+	return c.interceptQuery(ctx, info, func(ctx context.Context) (*abcitypes.ResponseQuery, error) {
+		info.Attempts++
+		return c.queryBackend.Query(ctx, method, data, opts)
+	})
+}
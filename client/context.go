@@ -3,16 +3,57 @@ package client
 import (
 	"github.com/cosmos/cosmos-sdk/codec"
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client/options"
 )
 
 // Context represents a context related to the Cosmos SDK with a ProtoCodec for encoding and decoding.
+// It holds the default query and transaction options, over which the options of each call are merged.
 type Context struct {
 	*codec.ProtoCodec
+
+	queryOptions      *options.QueryOptions // queryOptions are the default options of the queries.
+	txOptions         *options.TxOptions    // txOptions are the default options of the transactions.
+	queryInterceptors []QueryInterceptor    // queryInterceptors are called around each ABCI query.
+	txInterceptors    []TxInterceptor       // txInterceptors are called around each transaction broadcast.
+	queryBackend      QueryBackend          // queryBackend performs the gRPC queries, or nil for ABCI.
+	txBackend         TxBackend             // txBackend broadcasts the transactions, or nil for the RPC address.
 }
 
 // NewContext creates a new context with the provided InterfaceRegistry for encoding and decoding messages.
+// The default query and transaction options are options.Query and options.Tx, to be completed with
+// WithQueryOptions and WithTxOptions, for example with the RPC address and the chain ID.
 func NewContext(ir codectypes.InterfaceRegistry) *Context {
 	return &Context{
-		ProtoCodec: codec.NewProtoCodec(ir),
+		ProtoCodec:   codec.NewProtoCodec(ir),
+		queryOptions: options.Query(),
+		txOptions:    options.Tx(),
 	}
 }
+
+// WithQueryOptions merges the given options over the default query options of the Context,
+// and returns the modified instance.
+func (c *Context) WithQueryOptions(v *options.QueryOptions) *Context {
+	c.queryOptions = c.queryOptions.Merge(v)
+	return c
+}
+
+// WithTxOptions merges the given options over the default transaction options of the Context,
+// and returns the modified instance.
+func (c *Context) WithTxOptions(v *options.TxOptions) *Context {
+	c.txOptions = c.txOptions.Merge(v)
+	return c
+}
+
+// QueryOptions returns the options of a query, made of the given options merged over the default
+// query options of the Context, as described by options.QueryOptions.Merge. The given options may be nil.
+// For example, options.Query().WithProve(false) turns off a proof requested by the default options.
+func (c *Context) QueryOptions(opts *options.QueryOptions) *options.QueryOptions {
+	return c.queryOptions.Merge(opts)
+}
+
+// TxOptions returns the options of a transaction, made of the given options merged over the default
+// transaction options of the Context, as described by options.TxOptions.Merge. The given options may be nil.
+func (c *Context) TxOptions(opts *options.TxOptions) *options.TxOptions {
+	return c.txOptions.Merge(opts)
+}
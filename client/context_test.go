@@ -0,0 +1,127 @@
+package client_test
+
+import (
+	"testing"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client/options"
+)
+
+func TestContext_TxOptions(t *testing.T) {
+	ctx := client.NewContext(codectypes.NewInterfaceRegistry()).
+		WithTxOptions(options.Tx().WithRPCAddr("http://127.0.0.1:26657").WithMaxRetries(5).WithTimeout(time.Minute).WithBroadcastMode("async"))
+
+	tests := []struct {
+		name  string
+		opts  *options.TxOptions
+		check func(t *testing.T, got *options.TxOptions)
+	}{
+		{
+			name: "nil options",
+			check: func(t *testing.T, got *options.TxOptions) {
+				if !got.SimulateAndExecute || got.MaxRetries != 5 {
+					t.Errorf("TxOptions() = %+v, want the defaults of the context", got)
+				}
+			},
+		},
+		{
+			name: "setter turns off a flag",
+			opts: options.Tx().WithSimulateAndExecute(false),
+			check: func(t *testing.T, got *options.TxOptions) {
+				if got.SimulateAndExecute {
+					t.Error("SimulateAndExecute = true, want false")
+				}
+			},
+		},
+		{
+			name: "pre-filled values keep the defaults of the context",
+			opts: options.Tx().WithChainID("sentinelhub-2"),
+			check: func(t *testing.T, got *options.TxOptions) {
+				if got.ChainID != "sentinelhub-2" {
+					t.Errorf("ChainID = %q, want %q", got.ChainID, "sentinelhub-2")
+				}
+				if got.MaxRetries != 5 || got.Timeout != time.Minute || got.BroadcastMode != "async" {
+					t.Errorf("TxOptions() = %+v, want the max retries, timeout and broadcast mode of the context", got)
+				}
+				if got.RPCAddr != "http://127.0.0.1:26657" {
+					t.Errorf("RPCAddr = %q, want the address of the context", got.RPCAddr)
+				}
+			},
+		},
+		{
+			name: "setter applies a default value",
+			opts: options.Tx().WithMaxRetries(options.DefaultTxMaxRetries),
+			check: func(t *testing.T, got *options.TxOptions) {
+				if got.MaxRetries != options.DefaultTxMaxRetries {
+					t.Errorf("MaxRetries = %d, want %d", got.MaxRetries, options.DefaultTxMaxRetries)
+				}
+			},
+		},
+		{
+			name: "struct literal applies non-zero values",
+			opts: &options.TxOptions{Gas: 200000},
+			check: func(t *testing.T, got *options.TxOptions) {
+				if got.Gas != 200000 || got.MaxRetries != 5 || !got.SimulateAndExecute {
+					t.Errorf("TxOptions() = %+v, want the gas over the defaults of the context", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, ctx.TxOptions(tt.opts))
+		})
+	}
+}
+
+func TestContext_QueryOptions(t *testing.T) {
+	ctx := client.NewContext(codectypes.NewInterfaceRegistry()).
+		WithQueryOptions(options.Query().WithRPCAddr("http://127.0.0.1:26657").WithMaxRetries(3).WithHeight(10).WithProve(true).WithPageReverse(true))
+
+	tests := []struct {
+		name  string
+		opts  *options.QueryOptions
+		check func(t *testing.T, got *options.QueryOptions)
+	}{
+		{
+			name: "setters turn off flags and the height",
+			opts: options.Query().WithHeight(0).WithProve(false).WithPageReverse(false),
+			check: func(t *testing.T, got *options.QueryOptions) {
+				if got.Height != 0 || got.Prove || got.PageReverse {
+					t.Errorf("QueryOptions() = %+v, want the height, prove and page reverse turned off", got)
+				}
+			},
+		},
+		{
+			name: "pre-filled values keep the defaults of the context",
+			opts: options.Query().WithPageLimit(2),
+			check: func(t *testing.T, got *options.QueryOptions) {
+				if got.PageLimit != 2 {
+					t.Errorf("PageLimit = %d, want 2", got.PageLimit)
+				}
+				if got.MaxRetries != 3 || got.Height != 10 || !got.Prove || got.RPCAddr != "http://127.0.0.1:26657" {
+					t.Errorf("QueryOptions() = %+v, want the defaults of the context", got)
+				}
+			},
+		},
+		{
+			name: "struct literal applies non-zero values",
+			opts: &options.QueryOptions{Height: 5},
+			check: func(t *testing.T, got *options.QueryOptions) {
+				if got.Height != 5 || got.MaxRetries != 3 || !got.Prove {
+					t.Errorf("QueryOptions() = %+v, want the height over the defaults of the context", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, ctx.QueryOptions(tt.opts))
+		})
+	}
+}
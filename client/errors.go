@@ -0,0 +1,44 @@
+package client
+
+import (
+	"fmt"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// QueryError is returned when an ABCI query is answered with a non-zero code.
+// It wraps sentinelsdk.ErrNotFound when the code denotes a missing item, such as a gRPC NotFound
+// status returned by a query service, so that callers can test for it with errors.Is.
+type QueryError struct {
+	Code      uint32 // Code is the ABCI response code.
+	Codespace string // Codespace is the namespace of the response code.
+	Log       string // Log is the error message of the response.
+	Path      string // Path is the ABCI query path.
+}
+
+// Error returns a human-readable description of the QueryError.
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("query %s failed with code %d in codespace %s: %s", e.Path, e.Code, e.Codespace, e.Log)
+}
+
+// IsNotFound checks whether the response code denotes a missing item.
+// Query services report missing items with the gRPC NotFound status, which is answered
+// with the key not found code of the root codespace.
+func (e *QueryError) IsNotFound() bool {
+	if e.Codespace != sdkerrors.RootCodespace {
+		return false
+	}
+
+	return e.Code == sdkerrors.ErrKeyNotFound.ABCICode() || e.Code == sdkerrors.ErrNotFound.ABCICode()
+}
+
+// Unwrap returns sentinelsdk.ErrNotFound if the response code denotes a missing item, and nil otherwise.
+func (e *QueryError) Unwrap() error {
+	if e.IsNotFound() {
+		return sentinelsdk.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client"
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+func TestQueryError(t *testing.T) {
+	tests := []struct {
+		name         string
+		code         uint32
+		codespace    string
+		wantNotFound bool
+	}{
+		{
+			name:         "key not found",
+			code:         sdkerrors.ErrKeyNotFound.ABCICode(),
+			codespace:    sdkerrors.RootCodespace,
+			wantNotFound: true,
+		},
+		{
+			name:         "not found",
+			code:         sdkerrors.ErrNotFound.ABCICode(),
+			codespace:    sdkerrors.RootCodespace,
+			wantNotFound: true,
+		},
+		{
+			name:      "invalid request",
+			code:      sdkerrors.ErrInvalidRequest.ABCICode(),
+			codespace: sdkerrors.RootCodespace,
+		},
+		{
+			name:      "unauthorized",
+			code:      sdkerrors.ErrUnauthorized.ABCICode(),
+			codespace: sdkerrors.RootCodespace,
+		},
+		{
+			name:      "not found code of another codespace",
+			code:      sdkerrors.ErrNotFound.ABCICode(),
+			codespace: "node",
+		},
+		{
+			name:      "key not found code of another codespace",
+			code:      sdkerrors.ErrKeyNotFound.ABCICode(),
+			codespace: "session",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error = &client.QueryError{Code: tt.code, Codespace: tt.codespace, Log: "failed", Path: "/test"}
+
+			if got := errors.Is(err, sentinelsdk.ErrNotFound); got != tt.wantNotFound {
+				t.Errorf("errors.Is(err, ErrNotFound) = %t, want %t", got, tt.wantNotFound)
+			}
+
+			var queryErr *client.QueryError
+			if !errors.As(err, &queryErr) {
+				t.Fatal("errors.As(err, *QueryError) = false, want true")
+			}
+			if queryErr.Code != tt.code || queryErr.Codespace != tt.codespace {
+				t.Errorf("QueryError = %d in %s, want %d in %s", queryErr.Code, queryErr.Codespace, tt.code, tt.codespace)
+			}
+			if queryErr.IsNotFound() != tt.wantNotFound {
+				t.Errorf("IsNotFound() = %t, want %t", queryErr.IsNotFound(), tt.wantNotFound)
+			}
+		})
+	}
+}
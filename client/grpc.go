@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client/options"
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+var _ QueryBackend = (*GRPCBackend)(nil)
+
+// GRPCBackend is a QueryBackend performing the queries over the native gRPC endpoint of a node,
+// usually listening on port 9090. The queries share a single connection, which is re-established
+// by gRPC when it breaks, so the backend is created once and closed when it is no longer used.
+type GRPCBackend struct {
+	addr string           // addr is the address of the gRPC endpoint.
+	conn *grpc.ClientConn // conn is the connection shared by the queries.
+}
+
+// NewGRPCBackend creates a new gRPC backend for the given address, such as "localhost:9090".
+// The connection is insecure unless the dial options set other transport credentials.
+// The connection is established in the background, so that an unreachable endpoint fails the queries.
+func NewGRPCBackend(addr string, opts ...grpc.DialOption) (*GRPCBackend, error) {
+	// Prepend the insecure credentials, which are overridden by the credentials of the dial options.
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial grpc endpoint %s: %w", addr, err)
+	}
+
+	return &GRPCBackend{
+		addr: addr,
+		conn: conn,
+	}, nil
+}
+
+// Close closes the connection of the backend.
+func (b *GRPCBackend) Close() error {
+	return b.conn.Close()
+}
+
+// Endpoint returns the address of the gRPC endpoint.
+func (b *GRPCBackend) Endpoint() string {
+	return b.addr
+}
+
+// Query performs the query of the given gRPC method over the connection of the backend.
+// A non-zero height of the options is sent in the x-cosmos-block-height metadata, and the height the
+// query was answered at is read from the same header. The Prove option is not supported over gRPC.
+func (b *GRPCBackend) Query(ctx context.Context, method string, data []byte, opts *options.QueryOptions) (*abcitypes.ResponseQuery, error) {
+	// Limit the query to the timeout of the options.
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	// Query at the height of the options, if set.
+	if opts.Height > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(opts.Height, 10))
+	}
+
+	// Invoke the method with the marshalled request, keeping the response marshalled.
+	var (
+		header metadata.MD
+		value  []byte
+	)
+
+	err := b.conn.Invoke(ctx, method, data, &value, grpc.ForceCodec(rawCodec{}), grpc.Header(&header))
+	if err != nil {
+		return nil, grpcError(method, err)
+	}
+
+	// Read the height the query was answered at.
+	var height int64
+	if values := header.Get(grpctypes.GRPCBlockHeightHeader); len(values) > 0 {
+		height, err = strconv.ParseInt(values[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block height header %s: %w", values[0], err)
+		}
+	}
+
+	return &abcitypes.ResponseQuery{
+		Height: height,
+		Value:  value,
+	}, nil
+}
+
+// grpcError maps the status of a failed gRPC query to the errors of the package, so that a missing item,
+// a canceled query and a timed out query are reported like those of the ABCI queries.
+func grpcError(method string, err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	switch s.Code() {
+	case codes.NotFound:
+		return fmt.Errorf("query %s: %w: %s", method, sentinelsdk.ErrNotFound, s.Message())
+	case codes.Canceled:
+		return fmt.Errorf("query %s: %w: %s", method, context.Canceled, s.Message())
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("query %s: %w: %s", method, context.DeadlineExceeded, s.Message())
+	default:
+		return err
+	}
+}
+
+// rawCodec is a gRPC codec passing the marshalled messages through, since the requests and the responses
+// are marshalled and unmarshalled by the Context.
+type rawCodec struct{}
+
+// Marshal returns the marshalled request, given as a byte slice.
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	data, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected request type %T", v)
+	}
+
+	return data, nil
+}
+
+// Unmarshal stores the marshalled response into v, given as a pointer to a byte slice.
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	value, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("unexpected response type %T", v)
+	}
+
+	// Copy the data, which may be reused by gRPC.
+	*value = append([]byte(nil), data...)
+	return nil
+}
+
+// Name returns the name of the codec, the one of the protobuf codec, so that the content type
+// of the messages is the one expected by the server.
+func (rawCodec) Name() string {
+	return "proto"
+}
@@ -0,0 +1,190 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client/options"
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// bytesCodec is a server codec passing the marshalled messages through, like the one of the backend.
+type bytesCodec struct{}
+
+func (bytesCodec) Marshal(v interface{}) ([]byte, error) {
+	data, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+
+	return data, nil
+}
+
+func (bytesCodec) Unmarshal(data []byte, v interface{}) error {
+	value, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+
+	*value = append([]byte(nil), data...)
+	return nil
+}
+
+func (bytesCodec) Name() string {
+	return "proto"
+}
+
+// fakeQueryServer handles every method of a gRPC server:
+//   - /test.Query/Echo answers with the request, at the requested height or at height 42.
+//   - /test.Query/Missing fails with codes.NotFound.
+//   - /test.Query/Slow blocks until the query is canceled.
+//   - /test.Query/BadHeight answers with an invalid height header.
+func fakeQueryServer(_ interface{}, stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+
+	var req []byte
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+
+	switch method {
+	case "/test.Query/Echo":
+		height := "42"
+		if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
+			if values := md.Get(grpctypes.GRPCBlockHeightHeader); len(values) > 0 {
+				height = values[0]
+			}
+		}
+		if err := stream.SetHeader(metadata.Pairs(grpctypes.GRPCBlockHeightHeader, height)); err != nil {
+			return err
+		}
+
+		return stream.SendMsg(req)
+	case "/test.Query/Missing":
+		return status.Error(codes.NotFound, "item not found")
+	case "/test.Query/Slow":
+		<-stream.Context().Done()
+		return status.FromContextError(stream.Context().Err()).Err()
+	case "/test.Query/BadHeight":
+		if err := stream.SetHeader(metadata.Pairs(grpctypes.GRPCBlockHeightHeader, "abc")); err != nil {
+			return err
+		}
+
+		return stream.SendMsg(req)
+	default:
+		return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+	}
+}
+
+// newGRPCBackend starts an in-process gRPC server and returns a backend connected to it.
+func newGRPCBackend(t *testing.T) *client.GRPCBackend {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.ForceServerCodec(bytesCodec{}), grpc.UnknownServiceHandler(fakeQueryServer))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	backend, err := client.NewGRPCBackend("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewGRPCBackend() error = %v", err)
+	}
+	t.Cleanup(func() { _ = backend.Close() })
+
+	return backend
+}
+
+func TestGRPCBackend_Query(t *testing.T) {
+	backend := newGRPCBackend(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		opts       *options.QueryOptions
+		wantHeight int64
+		wantErr    error
+	}{
+		{
+			name:       "latest height",
+			method:     "/test.Query/Echo",
+			opts:       &options.QueryOptions{},
+			wantHeight: 42,
+		},
+		{
+			name:       "given height",
+			method:     "/test.Query/Echo",
+			opts:       &options.QueryOptions{Height: 1000},
+			wantHeight: 1000,
+		},
+		{
+			name:    "not found",
+			method:  "/test.Query/Missing",
+			opts:    &options.QueryOptions{},
+			wantErr: sentinelsdk.ErrNotFound,
+		},
+		{
+			name:    "deadline exceeded",
+			method:  "/test.Query/Slow",
+			opts:    &options.QueryOptions{Timeout: 50 * time.Millisecond},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := backend.Query(context.Background(), tt.method, []byte("request"), tt.opts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Query() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+
+			if res.Height != tt.wantHeight {
+				t.Errorf("Query() height = %d, want %d", res.Height, tt.wantHeight)
+			}
+			if string(res.Value) != "request" {
+				t.Errorf("Query() value = %q, want %q", res.Value, "request")
+			}
+		})
+	}
+}
+
+func TestGRPCBackend_Query_Canceled(t *testing.T) {
+	backend := newGRPCBackend(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := backend.Query(ctx, "/test.Query/Slow", []byte("request"), &options.QueryOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Query() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestGRPCBackend_Query_InvalidHeight(t *testing.T) {
+	backend := newGRPCBackend(t)
+
+	if _, err := backend.Query(context.Background(), "/test.Query/BadHeight", []byte("request"), &options.QueryOptions{}); err == nil {
+		t.Fatal("Query() error = nil, want an error for an invalid height header")
+	}
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// Error classes reported by ErrorClass.
+const (
+	ErrorClassNone       = ""
+	ErrorClassABCI       = "abci"
+	ErrorClassCanceled   = "canceled"
+	ErrorClassMaxRetries = "max_retries"
+	ErrorClassNotFound   = "not_found"
+	ErrorClassRPC        = "rpc"
+	ErrorClassTimeout    = "timeout"
+)
+
+// QueryInfo describes an ABCI query performed by the Context.
+// Attempts is updated while the query is performed, so interceptors read it once the query returns.
+type QueryInfo struct {
+	Attempts int    // Attempts is the number of attempts made to perform the query.
+	Endpoint string // Endpoint is the RPC address the query is sent to.
+	Height   int64  // Height is the height the query is performed at, zero for the latest height.
+	Path     string // Path is the ABCI query path, such as the gRPC method name.
+}
+
+// QueryInvoker performs an ABCI query.
+type QueryInvoker func(ctx context.Context) (*abcitypes.ResponseQuery, error)
+
+// QueryInterceptor intercepts an ABCI query performed by the Context, calling next to perform it.
+// Interceptors may record metrics from the info and the result, or wrap next in a tracing span
+// by passing a derived context to it.
+type QueryInterceptor func(ctx context.Context, info *QueryInfo, next QueryInvoker) (*abcitypes.ResponseQuery, error)
+
+// TxInfo describes a transaction broadcast performed by the Context.
+// Attempts is updated while the transaction is broadcast, so interceptors read it once the broadcast returns.
+type TxInfo struct {
+	Attempts int    // Attempts is the number of attempts made to broadcast the transaction.
+	Endpoint string // Endpoint is the RPC address the transaction is broadcast to.
+	Hash     string // Hash is the hex encoded hash of the transaction.
+	Mode     string // Mode is the broadcast mode, such as "sync".
+}
+
+// TxInvoker broadcasts a transaction.
+type TxInvoker func(ctx context.Context) (*coretypes.ResultBroadcastTx, error)
+
+// TxInterceptor intercepts a transaction broadcast performed by the Context, calling next to perform it.
+type TxInterceptor func(ctx context.Context, info *TxInfo, next TxInvoker) (*coretypes.ResultBroadcastTx, error)
+
+// WithQueryInterceptors appends the given query interceptors to the Context and returns the modified instance.
+// The first interceptor is the outermost one, called before the others.
+func (c *Context) WithQueryInterceptors(v ...QueryInterceptor) *Context {
+	c.queryInterceptors = append(c.queryInterceptors, v...)
+	return c
+}
+
+// WithTxInterceptors appends the given transaction interceptors to the Context and returns the modified instance.
+// The first interceptor is the outermost one, called before the others.
+func (c *Context) WithTxInterceptors(v ...TxInterceptor) *Context {
+	c.txInterceptors = append(c.txInterceptors, v...)
+	return c
+}
+
+// interceptQuery performs an ABCI query through the chain of query interceptors.
+func (c *Context) interceptQuery(ctx context.Context, info *QueryInfo, invoker QueryInvoker) (*abcitypes.ResponseQuery, error) {
+	// Wrap the invoker with each interceptor, starting from the innermost one.
+	for i := len(c.queryInterceptors) - 1; i >= 0; i-- {
+		interceptor, next := c.queryInterceptors[i], invoker
+		invoker = func(ctx context.Context) (*abcitypes.ResponseQuery, error) {
+			return interceptor(ctx, info, next)
+		}
+	}
+
+	return invoker(ctx)
+}
+
+// interceptTx broadcasts a transaction through the chain of transaction interceptors.
+func (c *Context) interceptTx(ctx context.Context, info *TxInfo, invoker TxInvoker) (*coretypes.ResultBroadcastTx, error) {
+	// Wrap the invoker with each interceptor, starting from the innermost one.
+	for i := len(c.txInterceptors) - 1; i >= 0; i-- {
+		interceptor, next := c.txInterceptors[i], invoker
+		invoker = func(ctx context.Context) (*coretypes.ResultBroadcastTx, error) {
+			return interceptor(ctx, info, next)
+		}
+	}
+
+	return invoker(ctx)
+}
+
+// ErrorClass returns the class of an error returned by a query or a transaction broadcast,
+// suitable as a low-cardinality metric label. It returns ErrorClassNone for a nil error.
+func ErrorClass(err error) string {
+	if err == nil {
+		return ErrorClassNone
+	}
+
+	// Check for the errors of the context.
+	if errors.Is(err, context.Canceled) {
+		return ErrorClassCanceled
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassTimeout
+	}
+
+	// Check for network timeouts.
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorClassTimeout
+	}
+
+	if errors.Is(err, sentinelsdk.ErrMaxRetries) {
+		return ErrorClassMaxRetries
+	}
+
+	// Check for the errors answered by the queried node itself.
+	if errors.Is(err, sentinelsdk.ErrNotFound) {
+		return ErrorClassNotFound
+	}
+
+	var queryErr *QueryError
+	if errors.As(err, &queryErr) {
+		return ErrorClassABCI
+	}
+
+	return ErrorClassRPC
+}
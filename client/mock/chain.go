@@ -0,0 +1,225 @@
+package mock
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	sentinelhub "github.com/sentinel-official/hub/types"
+	nodetypes "github.com/sentinel-official/hub/x/node/types"
+	plantypes "github.com/sentinel-official/hub/x/plan/types"
+	providertypes "github.com/sentinel-official/hub/x/provider/types"
+	sessiontypes "github.com/sentinel-official/hub/x/session/types"
+	subscriptiontypes "github.com/sentinel-official/hub/x/subscription/types"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client"
+)
+
+// Endpoint is the address reported by the Chain to the interceptors of a client.Context.
+const Endpoint = "mock"
+
+var (
+	_ client.QueryBackend = (*Chain)(nil)
+	_ client.TxBackend    = (*Chain)(nil)
+)
+
+// Chain is an in-memory chain answering the typed queries of a client.Context, for testing the code built
+// on it without a node. It is seeded with nodes, plans, providers, sessions, subscriptions and accounts,
+// answers the queries with pagination like the query services of the hub, and records the broadcast
+// transactions. It is set as both the query and the transaction backend of the Context:
+//
+//	chain := mock.NewChain(ctx.ProtoCodec).WithNodes(node)
+//	ctx.WithQueryBackend(chain).WithTxBackend(chain)
+type Chain struct {
+	mu  sync.RWMutex
+	cdc codec.Codec // cdc decodes the requests and encodes the responses.
+
+	height        int64                                              // height is the height the queries are answered at.
+	accounts      map[string]authtypes.AccountI                      // accounts are keyed by address.
+	allocations   map[uint64]map[string]subscriptiontypes.Allocation // allocations are keyed by subscription ID and address.
+	nodes         map[string]nodetypes.Node                          // nodes are keyed by address.
+	payouts       map[uint64]subscriptiontypes.Payout                // payouts are keyed by ID.
+	planNodes     map[uint64]map[string]bool                         // planNodes holds the addresses of the nodes of each plan.
+	plans         map[uint64]plantypes.Plan                          // plans are keyed by ID.
+	providers     map[string]providertypes.Provider                  // providers are keyed by address.
+	sessions      map[uint64]sessiontypes.Session                    // sessions are keyed by ID.
+	subscriptions map[uint64]subscriptiontypes.Subscription          // subscriptions are keyed by ID.
+	txs           [][]byte                                           // txs are the broadcast transactions, in order.
+}
+
+// NewChain creates a new empty chain. The codec must know the interfaces of the seeded accounts and
+// subscriptions, usually the codec of the client.Context.
+func NewChain(cdc codec.Codec) *Chain {
+	return &Chain{
+		cdc:           cdc,
+		height:        1,
+		accounts:      make(map[string]authtypes.AccountI),
+		allocations:   make(map[uint64]map[string]subscriptiontypes.Allocation),
+		nodes:         make(map[string]nodetypes.Node),
+		payouts:       make(map[uint64]subscriptiontypes.Payout),
+		planNodes:     make(map[uint64]map[string]bool),
+		plans:         make(map[uint64]plantypes.Plan),
+		providers:     make(map[string]providertypes.Provider),
+		sessions:      make(map[uint64]sessiontypes.Session),
+		subscriptions: make(map[uint64]subscriptiontypes.Subscription),
+	}
+}
+
+// WithHeight sets the height the queries are answered at and returns the modified instance.
+func (c *Chain) WithHeight(v int64) *Chain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.height = v
+	return c
+}
+
+// WithAccounts adds the given accounts, replacing those with the same address, and returns the modified instance.
+func (c *Chain) WithAccounts(v ...authtypes.AccountI) *Chain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, item := range v {
+		c.accounts[item.GetAddress().String()] = item
+	}
+
+	return c
+}
+
+// WithAllocations adds the given allocations to the subscription with the given ID, replacing those with
+// the same address, and returns the modified instance.
+func (c *Chain) WithAllocations(id uint64, v ...subscriptiontypes.Allocation) *Chain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.allocations[id] == nil {
+		c.allocations[id] = make(map[string]subscriptiontypes.Allocation)
+	}
+	for _, item := range v {
+		c.allocations[id][item.Address] = item
+	}
+
+	return c
+}
+
+// WithNodes adds the given nodes, replacing those with the same address, and returns the modified instance.
+func (c *Chain) WithNodes(v ...nodetypes.Node) *Chain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, item := range v {
+		c.nodes[item.Address] = item
+	}
+
+	return c
+}
+
+// WithPayouts adds the given payouts, replacing those with the same ID, and returns the modified instance.
+func (c *Chain) WithPayouts(v ...subscriptiontypes.Payout) *Chain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, item := range v {
+		c.payouts[item.ID] = item
+	}
+
+	return c
+}
+
+// WithPlanNodes links the nodes with the given addresses to the plan with the given ID,
+// and returns the modified instance.
+func (c *Chain) WithPlanNodes(id uint64, v ...sentinelhub.NodeAddress) *Chain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.planNodes[id] == nil {
+		c.planNodes[id] = make(map[string]bool)
+	}
+	for _, item := range v {
+		c.planNodes[id][item.String()] = true
+	}
+
+	return c
+}
+
+// WithPlans adds the given plans, replacing those with the same ID, and returns the modified instance.
+func (c *Chain) WithPlans(v ...plantypes.Plan) *Chain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, item := range v {
+		c.plans[item.ID] = item
+	}
+
+	return c
+}
+
+// WithProviders adds the given providers, replacing those with the same address, and returns the modified instance.
+func (c *Chain) WithProviders(v ...providertypes.Provider) *Chain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, item := range v {
+		c.providers[item.Address] = item
+	}
+
+	return c
+}
+
+// WithSessions adds the given sessions, replacing those with the same ID, and returns the modified instance.
+func (c *Chain) WithSessions(v ...sessiontypes.Session) *Chain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, item := range v {
+		c.sessions[item.ID] = item
+	}
+
+	return c
+}
+
+// WithSubscriptions adds the given subscriptions, replacing those with the same ID, and returns the modified instance.
+func (c *Chain) WithSubscriptions(v ...subscriptiontypes.Subscription) *Chain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, item := range v {
+		c.subscriptions[item.GetID()] = item
+	}
+
+	return c
+}
+
+// Endpoint returns the address reported to the interceptors.
+func (c *Chain) Endpoint() string {
+	return Endpoint
+}
+
+// BroadcastTx records the transaction and returns a successful result with its hash.
+func (c *Chain) BroadcastTx(_ context.Context, txBytes []byte, _ string) (*coretypes.ResultBroadcastTx, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx := append([]byte(nil), txBytes...)
+	c.txs = append(c.txs, tx)
+
+	return &coretypes.ResultBroadcastTx{
+		Hash: tmtypes.Tx(tx).Hash(),
+	}, nil
+}
+
+// Txs returns the broadcast transactions, in the order they were broadcast.
+func (c *Chain) Txs() [][]byte {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	res := make([][]byte, 0, len(c.txs))
+	for _, tx := range c.txs {
+		res = append(res, append([]byte(nil), tx...))
+	}
+
+	return res
+}
@@ -0,0 +1,267 @@
+package mock_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	cosmossdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	sentinelhub "github.com/sentinel-official/hub/types"
+	nodetypes "github.com/sentinel-official/hub/x/node/types"
+	plantypes "github.com/sentinel-official/hub/x/plan/types"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client/mock"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client/options"
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// nodeAddr returns the address of a node made of the given byte.
+func nodeAddr(b byte) sentinelhub.NodeAddress {
+	return bytes.Repeat([]byte{b}, 20)
+}
+
+// newContext returns a client.Context backed by a Chain seeded with five nodes, alternately active and
+// inactive, a plan and an account.
+func newContext(t *testing.T) (*client.Context, *mock.Chain) {
+	t.Helper()
+
+	ctx := client.NewContext(sentinelsdk.NewInterfaceRegistry())
+	chain := mock.NewChain(ctx.ProtoCodec).WithHeight(100)
+
+	for i := byte(1); i <= 5; i++ {
+		status := sentinelhub.StatusActive
+		if i%2 == 0 {
+			status = sentinelhub.StatusInactive
+		}
+
+		chain.WithNodes(nodetypes.Node{Address: nodeAddr(i).String(), Status: status})
+	}
+
+	chain.WithPlans(plantypes.Plan{ID: 1, Gigabytes: 10, Status: sentinelhub.StatusActive, StatusAt: time.Unix(1700000000, 0).UTC()})
+	chain.WithAccounts(authtypes.NewBaseAccount(cosmossdk.AccAddress(bytes.Repeat([]byte{0x01}, 20)), nil, 7, 3))
+
+	ctx.WithQueryBackend(chain).WithTxBackend(chain)
+	return ctx, chain
+}
+
+func TestChain_Query(t *testing.T) {
+	ctx, _ := newContext(t)
+
+	t.Run("node", func(t *testing.T) {
+		got, err := ctx.Node(context.Background(), nodeAddr(3), nil)
+		if err != nil {
+			t.Fatalf("Node() error = %v", err)
+		}
+
+		want := &nodetypes.Node{Address: nodeAddr(3).String(), Status: sentinelhub.StatusActive}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Node() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("missing node", func(t *testing.T) {
+		got, err := ctx.Node(context.Background(), nodeAddr(9), nil)
+		if err != nil || got != nil {
+			t.Fatalf("Node() = %v, %v, want nil, nil", got, err)
+		}
+	})
+
+	t.Run("nodes by status", func(t *testing.T) {
+		got, err := ctx.Nodes(context.Background(), sentinelhub.StatusInactive, nil)
+		if err != nil {
+			t.Fatalf("Nodes() error = %v", err)
+		}
+
+		want := []nodetypes.Node{
+			{Address: nodeAddr(2).String(), Status: sentinelhub.StatusInactive},
+			{Address: nodeAddr(4).String(), Status: sentinelhub.StatusInactive},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Nodes() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("plan", func(t *testing.T) {
+		got, err := ctx.Plan(context.Background(), 1, nil)
+		if err != nil {
+			t.Fatalf("Plan() error = %v", err)
+		}
+		if got == nil || got.ID != 1 || got.Gigabytes != 10 || !got.StatusAt.Equal(time.Unix(1700000000, 0)) {
+			t.Fatalf("Plan() = %v, want the seeded plan", got)
+		}
+	})
+
+	t.Run("account", func(t *testing.T) {
+		got, err := ctx.Account(context.Background(), bytes.Repeat([]byte{0x01}, 20), nil)
+		if err != nil {
+			t.Fatalf("Account() error = %v", err)
+		}
+		if got.GetAccountNumber() != 7 || got.GetSequence() != 3 {
+			t.Fatalf("Account() = %v, want account number 7 and sequence 3", got)
+		}
+	})
+
+	t.Run("endpoint and height", func(t *testing.T) {
+		var (
+			endpoint string
+			height   int64
+		)
+
+		ctx, _ := newContext(t)
+		ctx.WithQueryInterceptors(func(c context.Context, info *client.QueryInfo, next client.QueryInvoker) (*abcitypes.ResponseQuery, error) {
+			res, err := next(c)
+			if res != nil {
+				endpoint, height = info.Endpoint, res.Height
+			}
+
+			return res, err
+		})
+
+		if _, err := ctx.Node(context.Background(), nodeAddr(1), nil); err != nil {
+			t.Fatalf("Node() error = %v", err)
+		}
+		if endpoint != mock.Endpoint || height != 100 {
+			t.Fatalf("Node() endpoint = %q, height = %d, want %q, 100", endpoint, height, mock.Endpoint)
+		}
+	})
+}
+
+func TestChain_Query_Missing(t *testing.T) {
+	ctx, _ := newContext(t)
+
+	// Each query of a single item returns nil and a nil error for a missing item.
+	tests := []struct {
+		name  string
+		query func(c context.Context) (interface{}, error)
+	}{
+		{
+			name:  "node",
+			query: func(c context.Context) (interface{}, error) { return ctx.Node(c, nodeAddr(9), nil) },
+		},
+		{
+			name:  "plan",
+			query: func(c context.Context) (interface{}, error) { return ctx.Plan(c, 9, nil) },
+		},
+		{
+			name: "provider",
+			query: func(c context.Context) (interface{}, error) {
+				return ctx.Provider(c, bytes.Repeat([]byte{0x09}, 20), nil)
+			},
+		},
+		{
+			name:  "session",
+			query: func(c context.Context) (interface{}, error) { return ctx.Session(c, 9, nil) },
+		},
+		{
+			name:  "subscription",
+			query: func(c context.Context) (interface{}, error) { return ctx.Subscription(c, 9, nil) },
+		},
+		{
+			name: "subscription allocation",
+			query: func(c context.Context) (interface{}, error) {
+				return ctx.SubscriptionAllocation(c, 9, bytes.Repeat([]byte{0x09}, 20), nil)
+			},
+		},
+		{
+			name:  "subscription payout",
+			query: func(c context.Context) (interface{}, error) { return ctx.SubscriptionPayout(c, 9, nil) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query(context.Background())
+			if err != nil {
+				t.Fatalf("query error = %v, want nil", err)
+			}
+			if got != nil && !reflect.ValueOf(got).IsNil() {
+				t.Fatalf("query = %v, want nil", got)
+			}
+		})
+	}
+
+	// Account has no item to return for a missing account, and still fails.
+	t.Run("account", func(t *testing.T) {
+		got, err := ctx.Account(context.Background(), bytes.Repeat([]byte{0x09}, 20), nil)
+		if !errors.Is(err, sentinelsdk.ErrNotFound) {
+			t.Fatalf("Account() error = %v, want %v", err, sentinelsdk.ErrNotFound)
+		}
+		if got != nil {
+			t.Fatalf("Account() = %v, want nil", got)
+		}
+	})
+}
+
+func TestChain_Query_Pagination(t *testing.T) {
+	ctx, _ := newContext(t)
+
+	// Follow the next keys of the pages of two nodes.
+	var (
+		got   []string
+		pages int
+		key   []byte
+	)
+	for {
+		var resp nodetypes.QueryNodesResponse
+		req := &nodetypes.QueryNodesRequest{Pagination: &query.PageRequest{Key: key, Limit: 2}}
+		if err := ctx.QueryGRPC(context.Background(), "/sentinel.node.v2.QueryService/QueryNodes", req, &resp, nil); err != nil {
+			t.Fatalf("QueryGRPC() error = %v", err)
+		}
+
+		pages++
+		for _, item := range resp.Nodes {
+			got = append(got, item.Address)
+		}
+
+		key = resp.Pagination.GetNextKey()
+		if len(key) == 0 {
+			break
+		}
+	}
+
+	// The nodes are sorted by their bech32 addresses.
+	want := []string{nodeAddr(1).String(), nodeAddr(2).String(), nodeAddr(3).String(), nodeAddr(4).String(), nodeAddr(5).String()}
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("QueryNodes() pages = %v, want %v", got, want)
+	}
+	if pages != 3 {
+		t.Fatalf("QueryNodes() pages = %d, want 3", pages)
+	}
+
+	// Check the page options of the typed queries.
+	nodes, err := ctx.Nodes(context.Background(), sentinelhub.StatusUnspecified, options.Query().WithPageLimit(2).WithPageOffset(3))
+	if err != nil {
+		t.Fatalf("Nodes() error = %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].Address != want[3] || nodes[1].Address != want[4] {
+		t.Fatalf("Nodes() = %v, want the last two nodes", nodes)
+	}
+}
+
+func TestChain_BroadcastTx(t *testing.T) {
+	ctx, chain := newContext(t)
+
+	txs := [][]byte{[]byte("tx1"), []byte("tx2")}
+	for _, tx := range txs {
+		res, err := ctx.BroadcastTx(context.Background(), tx, nil)
+		if err != nil {
+			t.Fatalf("BroadcastTx() error = %v", err)
+		}
+		if len(res.Hash) == 0 {
+			t.Fatal("BroadcastTx() hash is empty")
+		}
+	}
+
+	if got := chain.Txs(); !reflect.DeepEqual(got, txs) {
+		t.Fatalf("Txs() = %q, want %q", got, txs)
+	}
+}
@@ -0,0 +1,57 @@
+package mock
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
+
+// paginate returns the bounds of the page of n sorted items requested by req, and the page response.
+// Like the query services of the chain, the page starts at the key or at the offset of the request,
+// holds at most query.DefaultLimit items if the request has no limit, and the total is counted when
+// requested, or when the request has no limit. The next key encodes the index of the next item.
+func paginate(n int, req *query.PageRequest) (start, end int, res *query.PageResponse, err error) {
+	if req == nil {
+		req = &query.PageRequest{}
+	}
+	if len(req.Key) > 0 && req.Offset > 0 {
+		return 0, 0, nil, errors.New("invalid request, either offset or key is expected, got both")
+	}
+
+	// Use the default limit, and count the total, if no limit is requested.
+	limit, countTotal := req.Limit, req.CountTotal
+	if limit == 0 {
+		limit, countTotal = query.DefaultLimit, true
+	}
+
+	// Start at the index encoded in the key, or at the offset.
+	if len(req.Key) > 0 {
+		if len(req.Key) != 8 {
+			return 0, 0, nil, errors.New("invalid pagination key")
+		}
+
+		start = int(binary.BigEndian.Uint64(req.Key))
+	} else {
+		start = int(req.Offset)
+	}
+
+	if start > n {
+		start = n
+	}
+
+	end = n
+	if uint64(n-start) > limit {
+		end = start + int(limit)
+	}
+
+	res = &query.PageResponse{}
+	if end < n {
+		res.NextKey = binary.BigEndian.AppendUint64(nil, uint64(end))
+	}
+	if countTotal && len(req.Key) == 0 {
+		res.Total = uint64(n)
+	}
+
+	return start, end, res, nil
+}
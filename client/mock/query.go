@@ -0,0 +1,648 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	sentinelhub "github.com/sentinel-official/hub/types"
+	nodetypes "github.com/sentinel-official/hub/x/node/types"
+	plantypes "github.com/sentinel-official/hub/x/plan/types"
+	providertypes "github.com/sentinel-official/hub/x/provider/types"
+	sessiontypes "github.com/sentinel-official/hub/x/session/types"
+	subscriptiontypes "github.com/sentinel-official/hub/x/subscription/types"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client/options"
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// Query answers the query of the given gRPC method from the seeded items, at the height of the Chain.
+// It supports the methods of the typed queries of client.Context, and returns an error wrapping
+// sentinelsdk.ErrNotFound if the queried item does not exist. The height of the options is ignored.
+func (c *Chain) Query(_ context.Context, method string, data []byte, _ *options.QueryOptions) (*abcitypes.ResponseQuery, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	handler, ok := handlers[method]
+	if !ok {
+		return nil, fmt.Errorf("unsupported method %s", method)
+	}
+
+	resp, err := handler(c, data)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := c.cdc.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &abcitypes.ResponseQuery{
+		Height: c.height,
+		Value:  value,
+	}, nil
+}
+
+// handler answers a query with the marshalled request, while the Chain is locked for reading.
+type handler func(c *Chain, data []byte) (codec.ProtoMarshaler, error)
+
+// handlers holds the handler of each supported gRPC method.
+var handlers = map[string]handler{
+	"/cosmos.auth.v1beta1.Query/Account": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req authtypes.QueryAccountRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		item, ok := c.accounts[req.Address]
+		if !ok {
+			return nil, fmt.Errorf("account %s: %w", req.Address, sentinelsdk.ErrNotFound)
+		}
+
+		account, err := codectypes.NewAnyWithValue(item)
+		if err != nil {
+			return nil, err
+		}
+
+		return &authtypes.QueryAccountResponse{Account: account}, nil
+	},
+	"/cosmos.auth.v1beta1.Query/Accounts": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req authtypes.QueryAccountsRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.accountPage(req.Pagination)
+		if err != nil {
+			return nil, err
+		}
+
+		return &authtypes.QueryAccountsResponse{Accounts: items, Pagination: pagination}, nil
+	},
+	"/sentinel.node.v2.QueryService/QueryNode": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req nodetypes.QueryNodeRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		item, ok := c.nodes[req.Address]
+		if !ok {
+			return nil, fmt.Errorf("node %s: %w", req.Address, sentinelsdk.ErrNotFound)
+		}
+
+		return &nodetypes.QueryNodeResponse{Node: item}, nil
+	},
+	"/sentinel.node.v2.QueryService/QueryNodes": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req nodetypes.QueryNodesRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.nodePage(req.Pagination, func(v nodetypes.Node) bool {
+			return matchStatus(req.Status, v.Status)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &nodetypes.QueryNodesResponse{Nodes: items, Pagination: pagination}, nil
+	},
+	"/sentinel.node.v2.QueryService/QueryNodesForPlan": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req nodetypes.QueryNodesForPlanRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.nodePage(req.Pagination, func(v nodetypes.Node) bool {
+			return c.planNodes[req.Id][v.Address] && matchStatus(req.Status, v.Status)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &nodetypes.QueryNodesForPlanResponse{Nodes: items, Pagination: pagination}, nil
+	},
+	"/sentinel.plan.v2.QueryService/QueryPlan": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req plantypes.QueryPlanRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		item, ok := c.plans[req.Id]
+		if !ok {
+			return nil, fmt.Errorf("plan %d: %w", req.Id, sentinelsdk.ErrNotFound)
+		}
+
+		return &plantypes.QueryPlanResponse{Plan: item}, nil
+	},
+	"/sentinel.plan.v2.QueryService/QueryPlans": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req plantypes.QueryPlansRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.planPage(req.Pagination, func(v plantypes.Plan) bool {
+			return matchStatus(req.Status, v.Status)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &plantypes.QueryPlansResponse{Plans: items, Pagination: pagination}, nil
+	},
+	"/sentinel.plan.v2.QueryService/QueryPlansForProvider": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req plantypes.QueryPlansForProviderRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.planPage(req.Pagination, func(v plantypes.Plan) bool {
+			return v.ProviderAddress == req.Address && matchStatus(req.Status, v.Status)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &plantypes.QueryPlansForProviderResponse{Plans: items, Pagination: pagination}, nil
+	},
+	"/sentinel.provider.v2.QueryService/QueryProvider": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req providertypes.QueryProviderRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		item, ok := c.providers[req.Address]
+		if !ok {
+			return nil, fmt.Errorf("provider %s: %w", req.Address, sentinelsdk.ErrNotFound)
+		}
+
+		return &providertypes.QueryProviderResponse{Provider: item}, nil
+	},
+	"/sentinel.provider.v2.QueryService/QueryProviders": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req providertypes.QueryProvidersRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.providerPage(req.Pagination, func(v providertypes.Provider) bool {
+			return matchStatus(req.Status, v.Status)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &providertypes.QueryProvidersResponse{Providers: items, Pagination: pagination}, nil
+	},
+	"/sentinel.session.v2.QueryService/QuerySession": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req sessiontypes.QuerySessionRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		item, ok := c.sessions[req.Id]
+		if !ok {
+			return nil, fmt.Errorf("session %d: %w", req.Id, sentinelsdk.ErrNotFound)
+		}
+
+		return &sessiontypes.QuerySessionResponse{Session: item}, nil
+	},
+	"/sentinel.session.v2.QueryService/QuerySessions": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req sessiontypes.QuerySessionsRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.sessionPage(req.Pagination, func(sessiontypes.Session) bool {
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &sessiontypes.QuerySessionsResponse{Sessions: items, Pagination: pagination}, nil
+	},
+	"/sentinel.session.v2.QueryService/QuerySessionsForAccount": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req sessiontypes.QuerySessionsForAccountRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.sessionPage(req.Pagination, func(v sessiontypes.Session) bool {
+			return v.Address == req.Address
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &sessiontypes.QuerySessionsForAccountResponse{Sessions: items, Pagination: pagination}, nil
+	},
+	"/sentinel.session.v2.QueryService/QuerySessionsForAllocation": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req sessiontypes.QuerySessionsForAllocationRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.sessionPage(req.Pagination, func(v sessiontypes.Session) bool {
+			return v.SubscriptionID == req.Id && v.Address == req.Address
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &sessiontypes.QuerySessionsForAllocationResponse{Sessions: items, Pagination: pagination}, nil
+	},
+	"/sentinel.session.v2.QueryService/QuerySessionsForNode": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req sessiontypes.QuerySessionsForNodeRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.sessionPage(req.Pagination, func(v sessiontypes.Session) bool {
+			return v.NodeAddress == req.Address
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &sessiontypes.QuerySessionsForNodeResponse{Sessions: items, Pagination: pagination}, nil
+	},
+	"/sentinel.session.v2.QueryService/QuerySessionsForSubscription": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req sessiontypes.QuerySessionsForSubscriptionRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.sessionPage(req.Pagination, func(v sessiontypes.Session) bool {
+			return v.SubscriptionID == req.Id
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &sessiontypes.QuerySessionsForSubscriptionResponse{Sessions: items, Pagination: pagination}, nil
+	},
+	"/sentinel.subscription.v2.QueryService/QueryAllocation": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req subscriptiontypes.QueryAllocationRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		item, ok := c.allocations[req.Id][req.Address]
+		if !ok {
+			return nil, fmt.Errorf("allocation %d/%s: %w", req.Id, req.Address, sentinelsdk.ErrNotFound)
+		}
+
+		return &subscriptiontypes.QueryAllocationResponse{Allocation: item}, nil
+	},
+	"/sentinel.subscription.v2.QueryService/QueryAllocations": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req subscriptiontypes.QueryAllocationsRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.allocationPage(req.Id, req.Pagination)
+		if err != nil {
+			return nil, err
+		}
+
+		return &subscriptiontypes.QueryAllocationsResponse{Allocations: items, Pagination: pagination}, nil
+	},
+	"/sentinel.subscription.v2.QueryService/QueryPayout": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req subscriptiontypes.QueryPayoutRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		item, ok := c.payouts[req.Id]
+		if !ok {
+			return nil, fmt.Errorf("payout %d: %w", req.Id, sentinelsdk.ErrNotFound)
+		}
+
+		return &subscriptiontypes.QueryPayoutResponse{Payout: item}, nil
+	},
+	"/sentinel.subscription.v2.QueryService/QueryPayouts": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req subscriptiontypes.QueryPayoutsRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.payoutPage(req.Pagination, func(subscriptiontypes.Payout) bool {
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &subscriptiontypes.QueryPayoutsResponse{Payouts: items, Pagination: pagination}, nil
+	},
+	"/sentinel.subscription.v2.QueryService/QueryPayoutsForAccount": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req subscriptiontypes.QueryPayoutsForAccountRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.payoutPage(req.Pagination, func(v subscriptiontypes.Payout) bool {
+			return v.Address == req.Address
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &subscriptiontypes.QueryPayoutsForAccountResponse{Payouts: items, Pagination: pagination}, nil
+	},
+	"/sentinel.subscription.v2.QueryService/QueryPayoutsForNode": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req subscriptiontypes.QueryPayoutsForNodeRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.payoutPage(req.Pagination, func(v subscriptiontypes.Payout) bool {
+			return v.NodeAddress == req.Address
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &subscriptiontypes.QueryPayoutsForNodeResponse{Payouts: items, Pagination: pagination}, nil
+	},
+	"/sentinel.subscription.v2.QueryService/QuerySubscription": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req subscriptiontypes.QuerySubscriptionRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		item, ok := c.subscriptions[req.Id]
+		if !ok {
+			return nil, fmt.Errorf("subscription %d: %w", req.Id, sentinelsdk.ErrNotFound)
+		}
+
+		subscription, err := codectypes.NewAnyWithValue(item)
+		if err != nil {
+			return nil, err
+		}
+
+		return &subscriptiontypes.QuerySubscriptionResponse{Subscription: subscription}, nil
+	},
+	"/sentinel.subscription.v2.QueryService/QuerySubscriptions": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req subscriptiontypes.QuerySubscriptionsRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.subscriptionPage(req.Pagination, func(subscriptiontypes.Subscription) bool {
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &subscriptiontypes.QuerySubscriptionsResponse{Subscriptions: items, Pagination: pagination}, nil
+	},
+	"/sentinel.subscription.v2.QueryService/QuerySubscriptionsForAccount": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req subscriptiontypes.QuerySubscriptionsForAccountRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		// Match the subscriptions of the account, and those the account has an allocation in.
+		items, pagination, err := c.subscriptionPage(req.Pagination, func(v subscriptiontypes.Subscription) bool {
+			_, ok := c.allocations[v.GetID()][req.Address]
+			return ok || v.GetAddress().String() == req.Address
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &subscriptiontypes.QuerySubscriptionsForAccountResponse{Subscriptions: items, Pagination: pagination}, nil
+	},
+	"/sentinel.subscription.v2.QueryService/QuerySubscriptionsForNode": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req subscriptiontypes.QuerySubscriptionsForNodeRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.subscriptionPage(req.Pagination, func(v subscriptiontypes.Subscription) bool {
+			s, ok := v.(*subscriptiontypes.NodeSubscription)
+			return ok && s.NodeAddress == req.Address
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &subscriptiontypes.QuerySubscriptionsForNodeResponse{Subscriptions: items, Pagination: pagination}, nil
+	},
+	"/sentinel.subscription.v2.QueryService/QuerySubscriptionsForPlan": func(c *Chain, data []byte) (codec.ProtoMarshaler, error) {
+		var req subscriptiontypes.QuerySubscriptionsForPlanRequest
+		if err := c.cdc.Unmarshal(data, &req); err != nil {
+			return nil, err
+		}
+
+		items, pagination, err := c.subscriptionPage(req.Pagination, func(v subscriptiontypes.Subscription) bool {
+			s, ok := v.(*subscriptiontypes.PlanSubscription)
+			return ok && s.PlanID == req.Id
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &subscriptiontypes.QuerySubscriptionsForPlanResponse{Subscriptions: items, Pagination: pagination}, nil
+	},
+}
+
+// matchStatus checks whether an item with the given status matches the status of a request,
+// where the unspecified status matches any item.
+func matchStatus(req, v sentinelhub.Status) bool {
+	return req.Equal(sentinelhub.StatusUnspecified) || v.Equal(req)
+}
+
+// accountPage returns the requested page of the accounts, sorted by address.
+func (c *Chain) accountPage(req *query.PageRequest) ([]*codectypes.Any, *query.PageResponse, error) {
+	var items []authtypes.AccountI
+	for _, item := range c.accounts {
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return (items[i].GetAddress().String() < items[j].GetAddress().String()) != req.GetReverse()
+	})
+
+	start, end, res, err := paginate(len(items), req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Pack the accounts of the page.
+	accounts := make([]*codectypes.Any, 0, end-start)
+	for _, item := range items[start:end] {
+		account, err := codectypes.NewAnyWithValue(item)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts, res, nil
+}
+
+// allocationPage returns the requested page of the allocations of a subscription, sorted by address.
+func (c *Chain) allocationPage(id uint64, req *query.PageRequest) ([]subscriptiontypes.Allocation, *query.PageResponse, error) {
+	var items []subscriptiontypes.Allocation
+	for _, item := range c.allocations[id] {
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return (items[i].Address < items[j].Address) != req.GetReverse()
+	})
+
+	start, end, res, err := paginate(len(items), req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return items[start:end], res, nil
+}
+
+// nodePage returns the requested page of the matching nodes, sorted by address.
+func (c *Chain) nodePage(req *query.PageRequest, match func(nodetypes.Node) bool) ([]nodetypes.Node, *query.PageResponse, error) {
+	var items []nodetypes.Node
+	for _, item := range c.nodes {
+		if match(item) {
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return (items[i].Address < items[j].Address) != req.GetReverse()
+	})
+
+	start, end, res, err := paginate(len(items), req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return items[start:end], res, nil
+}
+
+// payoutPage returns the requested page of the matching payouts, sorted by ID.
+func (c *Chain) payoutPage(req *query.PageRequest, match func(subscriptiontypes.Payout) bool) ([]subscriptiontypes.Payout, *query.PageResponse, error) {
+	var items []subscriptiontypes.Payout
+	for _, item := range c.payouts {
+		if match(item) {
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return (items[i].ID < items[j].ID) != req.GetReverse()
+	})
+
+	start, end, res, err := paginate(len(items), req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return items[start:end], res, nil
+}
+
+// planPage returns the requested page of the matching plans, sorted by ID.
+func (c *Chain) planPage(req *query.PageRequest, match func(plantypes.Plan) bool) ([]plantypes.Plan, *query.PageResponse, error) {
+	var items []plantypes.Plan
+	for _, item := range c.plans {
+		if match(item) {
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return (items[i].ID < items[j].ID) != req.GetReverse()
+	})
+
+	start, end, res, err := paginate(len(items), req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return items[start:end], res, nil
+}
+
+// providerPage returns the requested page of the matching providers, sorted by address.
+func (c *Chain) providerPage(req *query.PageRequest, match func(providertypes.Provider) bool) ([]providertypes.Provider, *query.PageResponse, error) {
+	var items []providertypes.Provider
+	for _, item := range c.providers {
+		if match(item) {
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return (items[i].Address < items[j].Address) != req.GetReverse()
+	})
+
+	start, end, res, err := paginate(len(items), req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return items[start:end], res, nil
+}
+
+// sessionPage returns the requested page of the matching sessions, sorted by ID.
+func (c *Chain) sessionPage(req *query.PageRequest, match func(sessiontypes.Session) bool) ([]sessiontypes.Session, *query.PageResponse, error) {
+	var items []sessiontypes.Session
+	for _, item := range c.sessions {
+		if match(item) {
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return (items[i].ID < items[j].ID) != req.GetReverse()
+	})
+
+	start, end, res, err := paginate(len(items), req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return items[start:end], res, nil
+}
+
+// subscriptionPage returns the requested page of the matching subscriptions, sorted by ID.
+func (c *Chain) subscriptionPage(req *query.PageRequest, match func(subscriptiontypes.Subscription) bool) ([]*codectypes.Any, *query.PageResponse, error) {
+	var items []subscriptiontypes.Subscription
+	for _, item := range c.subscriptions {
+		if match(item) {
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return (items[i].GetID() < items[j].GetID()) != req.GetReverse()
+	})
+
+	start, end, res, err := paginate(len(items), req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Pack the subscriptions of the page.
+	subscriptions := make([]*codectypes.Any, 0, end-start)
+	for _, item := range items[start:end] {
+		subscription, err := codectypes.NewAnyWithValue(item)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, res, nil
+}
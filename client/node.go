@@ -12,6 +12,7 @@ import (
 // Node queries and returns information about a specific node based on the provided node address.
 // It uses gRPC to send a request to the "/sentinel.node.v2.QueryService/QueryNode" endpoint.
 // The result is a pointer to nodetypes.Node and an error if the query fails.
+// It returns nil and a nil error if the node does not exist.
 func (c *Context) Node(ctx context.Context, nodeAddr sentinelhub.NodeAddress, opts *options.QueryOptions) (res *nodetypes.Node, err error) {
 	// Initialize variables for the query.
 	var (
@@ -22,8 +23,9 @@ func (c *Context) Node(ctx context.Context, nodeAddr sentinelhub.NodeAddress, op
 		}
 	)
 
-	// Send a gRPC query using the provided context, method, request, response, and options.
-	if err := c.QueryGRPC(ctx, method, req, &resp, opts); err != nil {
+	// Send a gRPC query using the provided context, method, request, response, and options,
+	// returning nil if the node does not exist.
+	if ok, err := c.queryGRPCItem(ctx, method, req, &resp, opts); !ok {
 		return nil, err
 	}
 
@@ -41,7 +43,7 @@ func (c *Context) Nodes(ctx context.Context, status sentinelhub.Status, opts *op
 		method = "/sentinel.node.v2.QueryService/QueryNodes"
 		req    = &nodetypes.QueryNodesRequest{
 			Status:     status,
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -66,7 +68,7 @@ func (c *Context) NodesForPlan(ctx context.Context, id uint64, status sentinelhu
 		req    = &nodetypes.QueryNodesForPlanRequest{
 			Id:         id,
 			Status:     status,
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -0,0 +1,118 @@
+package options
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// DefaultEnvPrefix is the prefix of the environment variables read by LoadQuery and LoadTx,
+// such as SENTINEL_RPC_ADDR and SENTINEL_GAS_PRICES.
+const DefaultEnvPrefix = "SENTINEL"
+
+// LoadQuery builds QueryOptions from the defaults, a configuration file and environment variables.
+// The values are applied in the following order, each one overriding the previous:
+//   - The default values returned by Query.
+//   - The values of the file, if not empty, in TOML, JSON or YAML format according to its extension.
+//   - The environment variables named after the fields with the prefix, if not empty, such as PREFIX_RPC_ADDR.
+//   - Each override, in order.
+//
+// Only the keys set by the file or the environment replace the defaults, so an explicit false or zero
+// value takes effect. The overrides are functions rather than options to merge for the same reason.
+// The resulting options are validated before being returned.
+func LoadQuery(file, envPrefix string, overrides ...func(*QueryOptions)) (*QueryOptions, error) {
+	// Read the values of the file and the environment variables over the defaults.
+	res := Query()
+	if err := load(file, envPrefix, res); err != nil {
+		return nil, err
+	}
+
+	// Apply the overrides in order.
+	for _, fn := range overrides {
+		fn(res)
+	}
+
+	// Mark every field as set, so the loaded options replace all the options they are merged over.
+	res.set = queryFieldAll
+
+	if err := res.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid query options: %w", err)
+	}
+
+	return res, nil
+}
+
+// LoadTx builds TxOptions from the defaults, a configuration file and environment variables.
+// The values are applied in the same order as LoadQuery, starting from the default values returned by Tx.
+// The resulting options are validated before being returned.
+func LoadTx(file, envPrefix string, overrides ...func(*TxOptions)) (*TxOptions, error) {
+	// Read the values of the file and the environment variables over the defaults.
+	res := Tx()
+	if err := load(file, envPrefix, res); err != nil {
+		return nil, err
+	}
+
+	// Apply the overrides in order.
+	for _, fn := range overrides {
+		fn(res)
+	}
+
+	// Mark every field as set, as for LoadQuery.
+	res.set = txFieldAll
+
+	if err := res.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tx options: %w", err)
+	}
+
+	return res, nil
+}
+
+// load decodes the values of a configuration file and of the prefixed environment variables into v,
+// a pointer to a struct whose fields are named by their json tags. Empty file or prefix skips the source.
+// Only the keys set by either source are decoded, leaving the other fields of v unchanged.
+func load(file, envPrefix string, v interface{}) error {
+	vp := viper.New()
+
+	// Read the configuration file, with the format given by its extension.
+	if file != "" {
+		vp.SetConfigFile(file)
+		if err := vp.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", file, err)
+		}
+	}
+
+	// Bind each field to its environment variable, such as PREFIX_RPC_ADDR for rpc_addr.
+	if envPrefix != "" {
+		vp.SetEnvPrefix(envPrefix)
+		for _, key := range keys(v) {
+			if err := vp.BindEnv(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Decode the values using the json tags, converting strings such as "15s" into durations.
+	if err := vp.Unmarshal(v, func(c *mapstructure.DecoderConfig) { c.TagName = "json" }); err != nil {
+		return fmt.Errorf("failed to decode options: %w", err)
+	}
+
+	return nil
+}
+
+// keys returns the names given by the json tags to the fields of the struct pointed to by v.
+func keys(v interface{}) []string {
+	t := reflect.TypeOf(v).Elem()
+
+	var res []string
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			res = append(res, name)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,142 @@
+package options_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client/options"
+)
+
+func TestLoadTx(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		env       map[string]string
+		overrides []func(*options.TxOptions)
+		check     func(t *testing.T, got *options.TxOptions)
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, got *options.TxOptions) {
+				if !got.SimulateAndExecute {
+					t.Errorf("SimulateAndExecute = false, want %t", options.DefaultTxSimulateAndExecute)
+				}
+				if got.Timeout != options.DefaultTxTimeout {
+					t.Errorf("Timeout = %s, want %s", got.Timeout, options.DefaultTxTimeout)
+				}
+			},
+		},
+		{
+			name: "file overrides defaults",
+			file: "simulate_and_execute = false\nmax_retries = 5\ntimeout = \"30s\"\n",
+			check: func(t *testing.T, got *options.TxOptions) {
+				if got.SimulateAndExecute {
+					t.Error("SimulateAndExecute = true, want false")
+				}
+				if options.Tx().Merge(got).SimulateAndExecute {
+					t.Error("Merge() SimulateAndExecute = true, want the false value of the loaded options")
+				}
+				if got.MaxRetries != 5 {
+					t.Errorf("MaxRetries = %d, want 5", got.MaxRetries)
+				}
+				if got.Timeout != 30*time.Second {
+					t.Errorf("Timeout = %s, want 30s", got.Timeout)
+				}
+				if got.BroadcastMode != options.DefaultTxBroadcastMode {
+					t.Errorf("BroadcastMode = %q, want %q", got.BroadcastMode, options.DefaultTxBroadcastMode)
+				}
+			},
+		},
+		{
+			name: "env overrides file",
+			file: "simulate_and_execute = true\nmax_retries = 5\n",
+			env: map[string]string{
+				"TEST_SIMULATE_AND_EXECUTE": "false",
+				"TEST_MAX_RETRIES":          "7",
+			},
+			check: func(t *testing.T, got *options.TxOptions) {
+				if got.SimulateAndExecute {
+					t.Error("SimulateAndExecute = true, want false")
+				}
+				if got.MaxRetries != 7 {
+					t.Errorf("MaxRetries = %d, want 7", got.MaxRetries)
+				}
+			},
+		},
+		{
+			name: "overrides override env",
+			file: "max_retries = 5\n",
+			env: map[string]string{
+				"TEST_SIMULATE_AND_EXECUTE": "true",
+				"TEST_MAX_RETRIES":          "7",
+			},
+			overrides: []func(*options.TxOptions){
+				func(o *options.TxOptions) { o.MaxRetries = 9 },
+				func(o *options.TxOptions) { o.SimulateAndExecute = false },
+			},
+			check: func(t *testing.T, got *options.TxOptions) {
+				if got.SimulateAndExecute {
+					t.Error("SimulateAndExecute = true, want false")
+				}
+				if got.MaxRetries != 9 {
+					t.Errorf("MaxRetries = %d, want 9", got.MaxRetries)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			file := ""
+			if tt.file != "" {
+				file = filepath.Join(t.TempDir(), "tx.toml")
+				if err := os.WriteFile(file, []byte(tt.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := options.LoadTx(file, "TEST", tt.overrides...)
+			if err != nil {
+				t.Fatalf("LoadTx() error = %v", err)
+			}
+
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestLoadQuery(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "query.json")
+	if err := os.WriteFile(file, []byte(`{"prove": true, "page_reverse": true, "page_limit": 10}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("TEST_PROVE", "false")
+
+	got, err := options.LoadQuery(file, "TEST", func(o *options.QueryOptions) { o.PageReverse = false })
+	if err != nil {
+		t.Fatalf("LoadQuery() error = %v", err)
+	}
+
+	if got.Prove {
+		t.Error("Prove = true, want false from the environment")
+	}
+	if got.PageReverse {
+		t.Error("PageReverse = true, want false from the override")
+	}
+	if got.PageLimit != 10 {
+		t.Errorf("PageLimit = %d, want 10", got.PageLimit)
+	}
+}
+
+func TestLoadTx_InvalidFile(t *testing.T) {
+	if _, err := options.LoadTx(filepath.Join(t.TempDir(), "missing.toml"), ""); err == nil {
+		t.Fatal("LoadTx() error = nil, want an error for a missing file")
+	}
+}
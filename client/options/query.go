@@ -2,6 +2,8 @@ package options
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/types/query"
@@ -18,6 +20,26 @@ const (
 	DefaultQueryWSEndpoint = "/websocket"
 )
 
+// queryField identifies a field of QueryOptions set with a With* setter.
+type queryField uint16
+
+// Fields of QueryOptions.
+const (
+	queryHeight queryField = 1 << iota
+	queryMaxRetries
+	queryPageCountTotal
+	queryPageKey
+	queryPageLimit
+	queryPageOffset
+	queryPageReverse
+	queryProve
+	queryRPCAddr
+	queryTimeout
+	queryWSEndpoint
+
+	queryFieldAll = 1<<iota - 1
+)
+
 // QueryOptions defines a set of options for making queries, including RPC and WebSocket configurations.
 type QueryOptions struct {
 	Height         int64         `json:"height,omitempty"`
@@ -31,6 +53,8 @@ type QueryOptions struct {
 	RPCAddr        string        `json:"rpc_addr,omitempty"`
 	Timeout        time.Duration `json:"timeout,omitempty"`
 	WSEndpoint     string        `json:"ws_endpoint,omitempty"`
+
+	set queryField // set records the fields set with the With* setters.
 }
 
 // Query creates and returns a new QueryOptions instance with default values.
@@ -42,6 +66,29 @@ func Query() *QueryOptions {
 	}
 }
 
+// Validate checks whether the QueryOptions are valid.
+func (q *QueryOptions) Validate() error {
+	if q == nil {
+		return errors.New("nil query options")
+	}
+	if q.Height < 0 {
+		return errors.New("height cannot be negative")
+	}
+	if q.MaxRetries < 0 {
+		return errors.New("max retries cannot be negative")
+	}
+	if q.RPCAddr != "" {
+		if _, err := url.ParseRequestURI(q.RPCAddr); err != nil {
+			return fmt.Errorf("invalid rpc address %s", q.RPCAddr)
+		}
+	}
+	if q.Timeout < 0 {
+		return errors.New("timeout cannot be negative")
+	}
+
+	return nil
+}
+
 // ABCIQueryOptions returns an ABCIQueryOptions instance based on the current QueryOptions.
 func (q *QueryOptions) ABCIQueryOptions() client.ABCIQueryOptions {
 	if q == nil {
@@ -63,6 +110,57 @@ func (q *QueryOptions) Client() (*http.HTTP, error) {
 	return http.NewWithTimeout(q.RPCAddr, q.WSEndpoint, utils.UIntSecondsFromDuration(q.Timeout))
 }
 
+// Merge returns a copy of the current QueryOptions with the values of v applied over it.
+// The fields of v set with the With* setters are applied, including zero values such as a false flag.
+// The other fields of v are applied only if they are neither zero nor equal to the defaults of Query,
+// so the values pre-filled by Query do not replace the current values.
+func (q *QueryOptions) Merge(v *QueryOptions) *QueryOptions {
+	res := &QueryOptions{}
+	if q != nil {
+		*res = *q
+	}
+	if v == nil {
+		return res
+	}
+
+	if v.set&queryHeight != 0 || v.Height != 0 {
+		res.Height = v.Height
+	}
+	if v.set&queryMaxRetries != 0 || (v.MaxRetries != 0 && v.MaxRetries != DefaultQueryMaxRetries) {
+		res.MaxRetries = v.MaxRetries
+	}
+	if v.set&queryPageCountTotal != 0 || v.PageCountTotal {
+		res.PageCountTotal = v.PageCountTotal
+	}
+	if v.set&queryPageKey != 0 || len(v.PageKey) != 0 {
+		res.PageKey = v.PageKey
+	}
+	if v.set&queryPageLimit != 0 || v.PageLimit != 0 {
+		res.PageLimit = v.PageLimit
+	}
+	if v.set&queryPageOffset != 0 || v.PageOffset != 0 {
+		res.PageOffset = v.PageOffset
+	}
+	if v.set&queryPageReverse != 0 || v.PageReverse {
+		res.PageReverse = v.PageReverse
+	}
+	if v.set&queryProve != 0 || v.Prove {
+		res.Prove = v.Prove
+	}
+	if v.set&queryRPCAddr != 0 || v.RPCAddr != "" {
+		res.RPCAddr = v.RPCAddr
+	}
+	if v.set&queryTimeout != 0 || (v.Timeout != 0 && v.Timeout != DefaultQueryTimeout) {
+		res.Timeout = v.Timeout
+	}
+	if v.set&queryWSEndpoint != 0 || (v.WSEndpoint != "" && v.WSEndpoint != DefaultQueryWSEndpoint) {
+		res.WSEndpoint = v.WSEndpoint
+	}
+
+	res.set |= v.set
+	return res
+}
+
 // PageRequest returns a PageRequest instance based on the current QueryOptions.
 func (q *QueryOptions) PageRequest() *query.PageRequest {
 	if q == nil {
@@ -81,65 +179,76 @@ func (q *QueryOptions) PageRequest() *query.PageRequest {
 // WithHeight sets the height in the current QueryOptions and returns the modified instance.
 func (q *QueryOptions) WithHeight(v int64) *QueryOptions {
 	q.Height = v
+	q.set |= queryHeight
 	return q
 }
 
 // WithMaxRetries sets the max retries in the current QueryOptions and returns the modified instance.
 func (q *QueryOptions) WithMaxRetries(v int) *QueryOptions {
 	q.MaxRetries = v
+	q.set |= queryMaxRetries
 	return q
 }
 
 // WithPageCountTotal sets the page count total flag in the current QueryOptions and returns the modified instance.
 func (q *QueryOptions) WithPageCountTotal(v bool) *QueryOptions {
 	q.PageCountTotal = v
+	q.set |= queryPageCountTotal
 	return q
 }
 
 // WithPageKey sets the page key in the current QueryOptions and returns the modified instance.
 func (q *QueryOptions) WithPageKey(v []byte) *QueryOptions {
 	q.PageKey = v
+	q.set |= queryPageKey
 	return q
 }
 
 // WithPageLimit sets the page limit in the current QueryOptions and returns the modified instance.
 func (q *QueryOptions) WithPageLimit(v uint64) *QueryOptions {
 	q.PageLimit = v
+	q.set |= queryPageLimit
 	return q
 }
 
 // WithPageOffset sets the page offset in the current QueryOptions and returns the modified instance.
 func (q *QueryOptions) WithPageOffset(v uint64) *QueryOptions {
 	q.PageOffset = v
+	q.set |= queryPageOffset
 	return q
 }
 
 // WithPageReverse sets the page reverse flag in the current QueryOptions and returns the modified instance.
 func (q *QueryOptions) WithPageReverse(v bool) *QueryOptions {
 	q.PageReverse = v
+	q.set |= queryPageReverse
 	return q
 }
 
 // WithProve sets the prove flag in the current QueryOptions and returns the modified instance.
 func (q *QueryOptions) WithProve(v bool) *QueryOptions {
 	q.Prove = v
+	q.set |= queryProve
 	return q
 }
 
 // WithRPCAddr sets the RPC address in the current QueryOptions and returns the modified instance.
 func (q *QueryOptions) WithRPCAddr(v string) *QueryOptions {
 	q.RPCAddr = v
+	q.set |= queryRPCAddr
 	return q
 }
 
 // WithTimeout sets the timeout in the current QueryOptions and returns the modified instance.
 func (q *QueryOptions) WithTimeout(v time.Duration) *QueryOptions {
 	q.Timeout = v
+	q.set |= queryTimeout
 	return q
 }
 
 // WithWSEndpoint sets the WebSocket endpoint in the current QueryOptions and returns the modified instance.
 func (q *QueryOptions) WithWSEndpoint(v string) *QueryOptions {
 	q.WSEndpoint = v
+	q.set |= queryWSEndpoint
 	return q
 }
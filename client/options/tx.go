@@ -1,7 +1,16 @@
 package options
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"time"
+
+	cosmossdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/tendermint/tendermint/rpc/client/http"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/utils"
 )
 
 // Default values for transaction options
@@ -9,11 +18,36 @@ const (
 	DefaultTxBroadcastMode      = "sync"
 	DefaultTxGasAdjustment      = 1.0 + (1.0 / 6)
 	DefaultTxMaxRetries         = 60
+	DefaultTxRetryDelay         = 500 * time.Millisecond
 	DefaultTxSimulateAndExecute = true
 	DefaultTxTimeout            = 15 * time.Second
 	DefaultTxWSEndpoint         = "/websocket"
 )
 
+// txField identifies a field of TxOptions set with a With* setter.
+type txField uint16
+
+// Fields of TxOptions.
+const (
+	txBroadcastMode txField = 1 << iota
+	txChainID
+	txFeeGranterAddr
+	txFees
+	txGasAdjustment
+	txGas
+	txGasPrices
+	txMaxRetries
+	txRetryDelay
+	txRPCAddr
+	txSignMode
+	txSimulateAndExecute
+	txTimeoutHeight
+	txTimeout
+	txWSEndpoint
+
+	txFieldAll = 1<<iota - 1
+)
+
 // TxOptions represents options for a transaction
 type TxOptions struct {
 	BroadcastMode      string        `json:"broadcast_mode,omitempty"`
@@ -24,12 +58,15 @@ type TxOptions struct {
 	Gas                int64         `json:"gas,omitempty"`
 	GasPrices          string        `json:"gas_prices,omitempty"`
 	MaxRetries         int           `json:"max_retries,omitempty"`
+	RetryDelay         time.Duration `json:"retry_delay,omitempty"`
 	RPCAddr            string        `json:"rpc_addr,omitempty"`
 	SignMode           string        `json:"sign_mode,omitempty"`
 	SimulateAndExecute bool          `json:"simulate_and_execute,omitempty"`
 	TimeoutHeight      int64         `json:"timeout_height,omitempty"`
 	Timeout            time.Duration `json:"timeout,omitempty"`
 	WSEndpoint         string        `json:"ws_endpoint,omitempty"`
+
+	set txField // set records the fields set with the With* setters.
 }
 
 // Tx creates a new TxOptions with default values
@@ -38,86 +75,251 @@ func Tx() *TxOptions {
 		BroadcastMode:      DefaultTxBroadcastMode,
 		GasAdjustment:      DefaultTxGasAdjustment,
 		MaxRetries:         DefaultTxMaxRetries,
+		RetryDelay:         DefaultTxRetryDelay,
 		SimulateAndExecute: DefaultTxSimulateAndExecute,
 		Timeout:            DefaultTxTimeout,
 		WSEndpoint:         DefaultTxWSEndpoint,
 	}
 }
 
+// Validate checks whether the TxOptions are valid
+func (t *TxOptions) Validate() error {
+	if t == nil {
+		return errors.New("nil tx options")
+	}
+
+	// Check the broadcast mode and the sign mode against the supported values.
+	switch t.BroadcastMode {
+	case "sync", "async":
+	default:
+		return fmt.Errorf("invalid broadcast mode %s; expected sync or async", t.BroadcastMode)
+	}
+	switch t.SignMode {
+	case "", "direct", "amino-json":
+	default:
+		return fmt.Errorf("invalid sign mode %s; expected direct or amino-json", t.SignMode)
+	}
+
+	// Check the fees and the gas prices, which are mutually exclusive.
+	if t.Fees != "" && t.GasPrices != "" {
+		return errors.New("fees and gas prices cannot be set together")
+	}
+	if t.Fees != "" {
+		if _, err := cosmossdk.ParseCoinsNormalized(t.Fees); err != nil {
+			return fmt.Errorf("invalid fees %s: %w", t.Fees, err)
+		}
+	}
+	if t.GasPrices != "" {
+		if _, err := cosmossdk.ParseDecCoins(t.GasPrices); err != nil {
+			return fmt.Errorf("invalid gas prices %s: %w", t.GasPrices, err)
+		}
+	}
+
+	if t.FeeGranterAddr != "" {
+		if _, _, err := bech32.DecodeAndConvert(t.FeeGranterAddr); err != nil {
+			return fmt.Errorf("invalid fee granter address %s: %w", t.FeeGranterAddr, err)
+		}
+	}
+	if t.GasAdjustment < 0 {
+		return errors.New("gas adjustment cannot be negative")
+	}
+	if t.Gas < 0 {
+		return errors.New("gas cannot be negative")
+	}
+	if t.MaxRetries < 0 {
+		return errors.New("max retries cannot be negative")
+	}
+	if t.RetryDelay < 0 {
+		return errors.New("retry delay cannot be negative")
+	}
+	if t.RPCAddr != "" {
+		if _, err := url.ParseRequestURI(t.RPCAddr); err != nil {
+			return fmt.Errorf("invalid rpc address %s", t.RPCAddr)
+		}
+	}
+	if t.TimeoutHeight < 0 {
+		return errors.New("timeout height cannot be negative")
+	}
+	if t.Timeout < 0 {
+		return errors.New("timeout cannot be negative")
+	}
+
+	return nil
+}
+
+// Client creates and returns an HTTP client based on the current TxOptions
+func (t *TxOptions) Client() (*http.HTTP, error) {
+	if t == nil {
+		return nil, errors.New("nil tx options")
+	}
+
+	return http.NewWithTimeout(t.RPCAddr, t.WSEndpoint, utils.UIntSecondsFromDuration(t.Timeout))
+}
+
+// Merge returns a copy of the current TxOptions with the values of v applied over it.
+// The fields of v set with the With* setters are applied, including zero values such as a false flag.
+// The other fields of v are applied only if they are neither zero nor equal to the defaults of Tx,
+// so the values pre-filled by Tx do not replace the current values
+func (t *TxOptions) Merge(v *TxOptions) *TxOptions {
+	res := &TxOptions{}
+	if t != nil {
+		*res = *t
+	}
+	if v == nil {
+		return res
+	}
+
+	if v.set&txBroadcastMode != 0 || (v.BroadcastMode != "" && v.BroadcastMode != DefaultTxBroadcastMode) {
+		res.BroadcastMode = v.BroadcastMode
+	}
+	if v.set&txChainID != 0 || v.ChainID != "" {
+		res.ChainID = v.ChainID
+	}
+	if v.set&txFeeGranterAddr != 0 || v.FeeGranterAddr != "" {
+		res.FeeGranterAddr = v.FeeGranterAddr
+	}
+	if v.set&txFees != 0 || v.Fees != "" {
+		res.Fees = v.Fees
+	}
+	if v.set&txGasAdjustment != 0 || (v.GasAdjustment != 0 && v.GasAdjustment != DefaultTxGasAdjustment) {
+		res.GasAdjustment = v.GasAdjustment
+	}
+	if v.set&txGas != 0 || v.Gas != 0 {
+		res.Gas = v.Gas
+	}
+	if v.set&txGasPrices != 0 || v.GasPrices != "" {
+		res.GasPrices = v.GasPrices
+	}
+	if v.set&txMaxRetries != 0 || (v.MaxRetries != 0 && v.MaxRetries != DefaultTxMaxRetries) {
+		res.MaxRetries = v.MaxRetries
+	}
+	if v.set&txRetryDelay != 0 || (v.RetryDelay != 0 && v.RetryDelay != DefaultTxRetryDelay) {
+		res.RetryDelay = v.RetryDelay
+	}
+	if v.set&txRPCAddr != 0 || v.RPCAddr != "" {
+		res.RPCAddr = v.RPCAddr
+	}
+	if v.set&txSignMode != 0 || v.SignMode != "" {
+		res.SignMode = v.SignMode
+	}
+	if v.set&txSimulateAndExecute != 0 {
+		res.SimulateAndExecute = v.SimulateAndExecute
+	}
+	if v.set&txTimeoutHeight != 0 || v.TimeoutHeight != 0 {
+		res.TimeoutHeight = v.TimeoutHeight
+	}
+	if v.set&txTimeout != 0 || (v.Timeout != 0 && v.Timeout != DefaultTxTimeout) {
+		res.Timeout = v.Timeout
+	}
+	if v.set&txWSEndpoint != 0 || (v.WSEndpoint != "" && v.WSEndpoint != DefaultTxWSEndpoint) {
+		res.WSEndpoint = v.WSEndpoint
+	}
+
+	res.set |= v.set
+	return res
+}
+
 // WithBroadcastMode sets the broadcast mode for the transaction
 func (t *TxOptions) WithBroadcastMode(v string) *TxOptions {
 	t.BroadcastMode = v
+	t.set |= txBroadcastMode
 	return t
 }
 
 // WithChainID sets the chain ID for the transaction
 func (t *TxOptions) WithChainID(v string) *TxOptions {
 	t.ChainID = v
+	t.set |= txChainID
 	return t
 }
 
 // WithFeeGranterAddr sets the fee granter address for the transaction
 func (t *TxOptions) WithFeeGranterAddr(v string) *TxOptions {
 	t.FeeGranterAddr = v
+	t.set |= txFeeGranterAddr
 	return t
 }
 
 // WithFees sets the fees for the transaction
 func (t *TxOptions) WithFees(v string) *TxOptions {
 	t.Fees = v
+	t.set |= txFees
 	return t
 }
 
 // WithGasAdjustment sets the gas adjustment for the transaction
 func (t *TxOptions) WithGasAdjustment(v float64) *TxOptions {
 	t.GasAdjustment = v
+	t.set |= txGasAdjustment
 	return t
 }
 
 // WithGas sets the gas limit for the transaction
 func (t *TxOptions) WithGas(v int64) *TxOptions {
 	t.Gas = v
+	t.set |= txGas
 	return t
 }
 
 // WithGasPrices sets the gas prices for the transaction
 func (t *TxOptions) WithGasPrices(v string) *TxOptions {
 	t.GasPrices = v
+	t.set |= txGasPrices
+	return t
+}
+
+// WithMaxRetries sets the maximum number of retries for the transaction
+func (t *TxOptions) WithMaxRetries(v int) *TxOptions {
+	t.MaxRetries = v
+	t.set |= txMaxRetries
+	return t
+}
+
+// WithRetryDelay sets the delay before the first retry of the broadcast, doubled after each retry
+func (t *TxOptions) WithRetryDelay(v time.Duration) *TxOptions {
+	t.RetryDelay = v
+	t.set |= txRetryDelay
 	return t
 }
 
 // WithRPCAddr sets the RPC address for the transaction
 func (t *TxOptions) WithRPCAddr(v string) *TxOptions {
 	t.RPCAddr = v
+	t.set |= txRPCAddr
 	return t
 }
 
 // WithSignMode sets the sign mode for the transaction
 func (t *TxOptions) WithSignMode(v string) *TxOptions {
 	t.SignMode = v
+	t.set |= txSignMode
 	return t
 }
 
 // WithSimulateAndExecute sets the simulate and execute flag for the transaction
 func (t *TxOptions) WithSimulateAndExecute(v bool) *TxOptions {
 	t.SimulateAndExecute = v
+	t.set |= txSimulateAndExecute
 	return t
 }
 
 // WithTimeoutHeight sets the timeout height for the transaction
 func (t *TxOptions) WithTimeoutHeight(v int64) *TxOptions {
 	t.TimeoutHeight = v
+	t.set |= txTimeoutHeight
 	return t
 }
 
 // WithTimeout sets the timeout duration for the transaction
 func (t *TxOptions) WithTimeout(v time.Duration) *TxOptions {
 	t.Timeout = v
+	t.set |= txTimeout
 	return t
 }
 
 // WithWSEndpoint sets the WebSocket endpoint for the transaction
 func (t *TxOptions) WithWSEndpoint(v string) *TxOptions {
 	t.WSEndpoint = v
+	t.set |= txWSEndpoint
 	return t
 }
@@ -12,6 +12,7 @@ import (
 // Plan queries and returns information about a specific plan based on the provided plan ID.
 // It uses gRPC to send a request to the "/sentinel.plan.v2.QueryService/QueryPlan" endpoint.
 // The result is a pointer to plantypes.Plan and an error if the query fails.
+// It returns nil and a nil error if the plan does not exist.
 func (c *Context) Plan(ctx context.Context, id uint64, opts *options.QueryOptions) (res *plantypes.Plan, err error) {
 	// Initialize variables for the query.
 	var (
@@ -22,8 +23,9 @@ func (c *Context) Plan(ctx context.Context, id uint64, opts *options.QueryOption
 		}
 	)
 
-	// Send a gRPC query using the provided context, method, request, response, and options.
-	if err := c.QueryGRPC(ctx, method, req, &resp, opts); err != nil {
+	// Send a gRPC query using the provided context, method, request, response, and options,
+	// returning nil if the plan does not exist.
+	if ok, err := c.queryGRPCItem(ctx, method, req, &resp, opts); !ok {
 		return nil, err
 	}
 
@@ -41,7 +43,7 @@ func (c *Context) Plans(ctx context.Context, status sentinelhub.Status, opts *op
 		method = "/sentinel.plan.v2.QueryService/QueryPlans"
 		req    = &plantypes.QueryPlansRequest{
 			Status:     status,
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -66,7 +68,7 @@ func (c *Context) PlansForProvider(ctx context.Context, provAddr sentinelhub.Pro
 		req    = &plantypes.QueryPlansForProviderRequest{
 			Address:    provAddr.String(),
 			Status:     status,
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -12,6 +12,7 @@ import (
 // Provider queries and returns information about a specific provider based on the provided provider address.
 // It uses gRPC to send a request to the "/sentinel.provider.v2.QueryService/QueryProvider" endpoint.
 // The result is a pointer to providertypes.Provider and an error if the query fails.
+// It returns nil and a nil error if the provider does not exist.
 func (c *Context) Provider(ctx context.Context, provAddr sentinelhub.ProvAddress, opts *options.QueryOptions) (res *providertypes.Provider, err error) {
 	// Initialize variables for the query.
 	var (
@@ -22,8 +23,9 @@ func (c *Context) Provider(ctx context.Context, provAddr sentinelhub.ProvAddress
 		}
 	)
 
-	// Send a gRPC query using the provided context, method, request, response, and options.
-	if err := c.QueryGRPC(ctx, method, req, &resp, opts); err != nil {
+	// Send a gRPC query using the provided context, method, request, response, and options,
+	// returning nil if the provider does not exist.
+	if ok, err := c.queryGRPCItem(ctx, method, req, &resp, opts); !ok {
 		return nil, err
 	}
 
@@ -41,7 +43,7 @@ func (c *Context) Providers(ctx context.Context, status sentinelhub.Status, opts
 		method = "/sentinel.provider.v2.QueryService/QueryProviders"
 		req    = &providertypes.QueryProvidersRequest{
 			Status:     status,
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
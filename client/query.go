@@ -11,42 +11,76 @@ import (
 	"github.com/tendermint/tendermint/libs/bytes"
 
 	"github.com/sentinel-official/sentinel-go-sdk/v1/client/options"
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
 )
 
+// isRetryable checks whether an RPC error is transient, such as an EOF or an HTML error page
+// returned by a proxy in front of the RPC server.
+func isRetryable(err error) bool {
+	return strings.Contains(err.Error(), "EOF") || strings.Contains(err.Error(), "invalid character '<' looking for beginning of value")
+}
+
 // ABCIQueryWithOptions performs an ABCI query with configurable options.
-// It retries the query according to the specified maximum number of retries.
+// It retries the query according to the specified maximum number of retries, and returns
+// a *QueryError if the query is answered with a non-zero code.
+// The options are merged over the default query options of the Context, and may be nil.
+// The query goes through the query interceptors of the Context.
 func (c *Context) ABCIQueryWithOptions(ctx context.Context, path string, data bytes.HexBytes, opts *options.QueryOptions) (*abcitypes.ResponseQuery, error) {
-	// Get the ABCI client from the provided options.
+	// Merge the provided options over the default options.
+	opts = c.QueryOptions(opts)
+
+	// Get the ABCI client from the merged options.
 	client, err := opts.Client()
 	if err != nil {
 		return nil, err
 	}
 
-	// Retry the query for the specified number of times.
-	for t := 0; t < opts.MaxRetries; t++ {
-		// Perform the ABCI query with options.
-		result, err := client.ABCIQueryWithOptions(ctx, path, data, opts.ABCIQueryOptions())
-		if err != nil {
-			// Retry on specific errors, such as EOF or invalid character.
-			if strings.Contains(err.Error(), "EOF") || strings.Contains(err.Error(), "invalid character '<' looking for beginning of value") {
-				continue
+	// Describe the query for the interceptors.
+	info := &QueryInfo{
+		Endpoint: opts.RPCAddr,
+		Height:   opts.Height,
+		Path:     path,
+	}
+
+	return c.interceptQuery(ctx, info, func(ctx context.Context) (*abcitypes.ResponseQuery, error) {
+		// Retry the query for the specified number of times.
+		for t := 0; t < opts.MaxRetries; t++ {
+			info.Attempts++
+
+			// Perform the ABCI query with options.
+			result, err := client.ABCIQueryWithOptions(ctx, path, data, opts.ABCIQueryOptions())
+			if err != nil {
+				// Retry on specific errors, such as EOF or invalid character.
+				if isRetryable(err) {
+					continue
+				}
+
+				// Return other errors.
+				return nil, err
 			}
 
-			// Return other errors.
-			return nil, err
-		}
+			// If the result is nil, return nil.
+			if result == nil {
+				return nil, nil
+			}
 
-		// If the result is nil, return nil.
-		if result == nil {
-			return nil, nil
-		}
+			// Return an error if the query was answered with a non-zero code.
+			if result.Response.Code != abcitypes.CodeTypeOK {
+				return nil, &QueryError{
+					Code:      result.Response.Code,
+					Codespace: result.Response.Codespace,
+					Log:       result.Response.Log,
+					Path:      path,
+				}
+			}
 
-		// Return the response from the successful query.
-		return &result.Response, nil
-	}
+			// Return the response from the successful query.
+			return &result.Response, nil
+		}
 
-	// Return an error if the maximum retry limit is reached.
-	return nil, errors.New("reached max retry limit")
+		// Return an error if the maximum retry limit is reached.
+		return nil, sentinelsdk.ErrMaxRetries
+	})
 }
 
 // QueryKey performs an ABCI query for a specific key in a store.
@@ -67,8 +101,9 @@ func (c *Context) QuerySubspace(ctx context.Context, store string, data bytes.He
 	return c.ABCIQueryWithOptions(ctx, path, data, opts)
 }
 
-// QueryGRPC performs a gRPC query using ABCI with configurable options.
-// It marshals the request, queries with ABCI, and unmarshals the response.
+// QueryGRPC performs a gRPC query with configurable options.
+// It marshals the request, queries with the backend of the Context, or with ABCI if none is set,
+// and unmarshals the response.
 func (c *Context) QueryGRPC(ctx context.Context, method string, req, resp codec.ProtoMarshaler, opts *options.QueryOptions) error {
 	// Marshal the gRPC request.
 	data, err := c.Marshal(req)
@@ -76,15 +111,20 @@ func (c *Context) QueryGRPC(ctx context.Context, method string, req, resp codec.
 		return err
 	}
 
-	// Perform ABCI query with options.
-	reply, err := c.ABCIQueryWithOptions(ctx, method, data, opts)
+	// Perform the query with the backend, or with ABCI.
+	var reply *abcitypes.ResponseQuery
+	if c.queryBackend != nil {
+		reply, err = c.queryBackendWithOptions(ctx, method, data, opts)
+	} else {
+		reply, err = c.ABCIQueryWithOptions(ctx, method, data, opts)
+	}
 	if err != nil {
 		return err
 	}
 
 	// Check for a nil reply.
 	if reply == nil {
-		return errors.New("nil reply")
+		return sentinelsdk.ErrNilReply
 	}
 
 	// Unmarshal the ABCI response value into the provided response object.
@@ -95,3 +135,19 @@ func (c *Context) QueryGRPC(ctx context.Context, method string, req, resp codec.
 	// Return nil on success.
 	return nil
 }
+
+// queryGRPCItem performs a gRPC query for a single item like QueryGRPC, reporting whether the item exists.
+// It returns false and a nil error if the query is answered with a code denoting a missing item,
+// so that a missing item is told apart from a failed query.
+func (c *Context) queryGRPCItem(ctx context.Context, method string, req, resp codec.ProtoMarshaler, opts *options.QueryOptions) (bool, error) {
+	if err := c.QueryGRPC(ctx, method, req, resp, opts); err != nil {
+		// Report a missing item without an error.
+		if errors.Is(err, sentinelsdk.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,446 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"github.com/gogo/protobuf/jsonpb"
+	gogoproto "github.com/gogo/protobuf/proto"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client/options"
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+var _ QueryBackend = (*RESTBackend)(nil)
+
+// RESTBackend is a QueryBackend performing the queries over the REST gateway (LCD) of a node,
+// usually listening on port 1317. The route of each gRPC method is read from the HTTP annotations
+// of its service definition, so any query method with a GET route is supported.
+type RESTBackend struct {
+	addr   string       // addr is the base URL of the REST gateway.
+	cdc    codec.Codec  // cdc encodes the requests and decodes the responses in JSON.
+	client *http.Client // client sends the HTTP requests.
+	routes sync.Map     // routes caches the restRoute of each gRPC method.
+}
+
+// NewRESTBackend creates a new REST backend for the given base URL, such as "http://localhost:1317".
+// The codec must know the interfaces of the queried types, such as the accounts, usually the codec
+// of the Context.
+func NewRESTBackend(addr string, cdc codec.Codec) *RESTBackend {
+	return &RESTBackend{
+		addr:   strings.TrimSuffix(addr, "/"),
+		cdc:    cdc,
+		client: http.DefaultClient,
+	}
+}
+
+// WithHTTPClient sets the HTTP client of the backend and returns the modified instance.
+func (b *RESTBackend) WithHTTPClient(v *http.Client) *RESTBackend {
+	b.client = v
+	return b
+}
+
+// Endpoint returns the base URL of the REST gateway.
+func (b *RESTBackend) Endpoint() string {
+	return b.addr
+}
+
+// Query performs the query of the given gRPC method with a GET request to its route on the REST gateway.
+// The fields of the request fill the parameters of the route, and the others are sent in the query string.
+// A non-zero height of the options is sent in the x-cosmos-block-height header, and the height the query
+// was answered at is read from the headers of the response. The Prove option is not supported over REST,
+// and the query fails if it is set.
+func (b *RESTBackend) Query(ctx context.Context, method string, data []byte, opts *options.QueryOptions) (*abcitypes.ResponseQuery, error) {
+	// Reject the queries requesting a proof, which the gateway cannot return.
+	if opts.Prove {
+		return nil, fmt.Errorf("query %s: proofs are not supported over rest", method)
+	}
+
+	route, err := b.route(method)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode the request and encode it in JSON, to read the values of its fields.
+	req := reflect.New(route.request).Interface().(codec.ProtoMarshaler)
+	if err := b.cdc.Unmarshal(data, req); err != nil {
+		return nil, err
+	}
+
+	// Encode the enums as numbers, since the names given by the String methods of some enums, such as
+	// "active" for the hub status, are not accepted by the gateway.
+	reqJSON, err := (&jsonpb.Marshaler{OrigName: true, EnumsAsInts: true}).MarshalToString(req)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := b.url(route.path, []byte(reqJSON))
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", method, err)
+	}
+
+	// Limit the query to the timeout of the options.
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, target, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query at the height of the options, if set.
+	if opts.Height > 0 {
+		httpReq.Header.Set(grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(opts.Height, 10))
+	}
+
+	httpResp, err := b.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, restError(method, httpResp.StatusCode, body)
+	}
+
+	// Decode the JSON response and encode it like the responses of the other backends.
+	body, err = normalizeEnums(route.response, body)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: failed to decode response: %w", method, err)
+	}
+
+	resp := reflect.New(route.response).Interface().(codec.ProtoMarshaler)
+	if err := b.cdc.UnmarshalJSON(body, resp); err != nil {
+		return nil, fmt.Errorf("query %s: failed to decode response: %w", method, err)
+	}
+
+	value, err := b.cdc.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the height the query was answered at, forwarded by the gateway as gRPC metadata.
+	var height int64
+	for _, key := range []string{"Grpc-Metadata-" + grpctypes.GRPCBlockHeightHeader, grpctypes.GRPCBlockHeightHeader} {
+		if s := httpResp.Header.Get(key); s != "" {
+			height, err = strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid block height header %s: %w", s, err)
+			}
+
+			break
+		}
+	}
+
+	return &abcitypes.ResponseQuery{
+		Height: height,
+		Value:  value,
+	}, nil
+}
+
+// url builds the URL of a query from the path template of its route, such as "/sentinel/nodes/{address}",
+// and the request encoded in JSON.
+func (b *RESTBackend) url(path string, reqJSON []byte) (string, error) {
+	// Decode the request, keeping the numbers as they are encoded.
+	var fields map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(reqJSON))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return "", err
+	}
+
+	values := url.Values{}
+	flatten("", fields, values)
+
+	// Replace each parameter of the path with the value of its field, such as {address} or {id=**}.
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		name, _, _ := strings.Cut(segment[1:len(segment)-1], "=")
+		segments[i] = url.PathEscape(values.Get(name))
+		values.Del(name)
+	}
+
+	target := b.addr + strings.Join(segments, "/")
+	if len(values) > 0 {
+		target += "?" + values.Encode()
+	}
+
+	return target, nil
+}
+
+// flatten adds the values of the decoded JSON fields to the values, naming the fields of the nested
+// messages with dots, such as pagination.limit. Empty strings, false flags and zero numbers are skipped,
+// while a string such as "0" is kept.
+func flatten(prefix string, fields map[string]interface{}, values url.Values) {
+	for key, value := range fields {
+		name := prefix + key
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(name+".", v, values)
+		case []interface{}:
+			for _, item := range v {
+				values.Add(name, fmt.Sprint(item))
+			}
+		case nil:
+		case bool:
+			if v {
+				values.Set(name, "true")
+			}
+		case json.Number:
+			if f, err := v.Float64(); err != nil || f != 0 {
+				values.Set(name, v.String())
+			}
+		case string:
+			if v != "" {
+				values.Set(name, v)
+			}
+		default:
+			values.Set(name, fmt.Sprint(v))
+		}
+	}
+}
+
+// normalizeEnums replaces the enum values of a JSON response of the given message type that are encoded
+// with the String methods of their enums, such as "active" for the hub status, with their proto names,
+// since only the proto names are decoded.
+func normalizeEnums(t reflect.Type, body []byte) ([]byte, error) {
+	var v interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	normalizeEnumFields(t, v)
+	return json.Marshal(v)
+}
+
+// normalizeEnumFields replaces the enum values of the decoded JSON value v of the message type t,
+// walking the nested messages and the repeated fields.
+func normalizeEnumFields(t reflect.Type, v interface{}) {
+	fields, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		// Read the name and the enum type of the field from its protobuf tag.
+		var name, enum string
+		for _, item := range strings.Split(field.Tag.Get("protobuf"), ",") {
+			if k, value, ok := strings.Cut(item, "="); ok {
+				switch k {
+				case "name":
+					name = value
+				case "enum":
+					enum = value
+				}
+			}
+		}
+
+		value, ok := fields[name]
+		if !ok {
+			continue
+		}
+
+		// Find the message type of the field, through the pointers and the slices.
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr || ft.Kind() == reflect.Slice {
+			ft = ft.Elem()
+		}
+
+		switch {
+		case enum != "":
+			if items, ok := value.([]interface{}); ok {
+				for j, item := range items {
+					items[j] = enumName(ft, enum, item)
+				}
+			} else {
+				fields[name] = enumName(ft, enum, value)
+			}
+		case ft.Kind() == reflect.Struct:
+			if items, ok := value.([]interface{}); ok {
+				for _, item := range items {
+					normalizeEnumFields(ft, item)
+				}
+			} else {
+				normalizeEnumFields(ft, value)
+			}
+		}
+	}
+}
+
+// enumName returns the proto name of an enum value of the Go type t and the proto type enum,
+// if the value is the one returned by the String method of the enum, or the value unchanged.
+func enumName(t reflect.Type, enum string, value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	values := gogoproto.EnumValueMap(enum)
+	if _, ok := values[s]; ok {
+		return value
+	}
+
+	for name, number := range values {
+		v := reflect.New(t).Elem()
+		v.SetInt(int64(number))
+		if stringer, ok := v.Interface().(fmt.Stringer); ok && stringer.String() == s {
+			return name
+		}
+	}
+
+	return value
+}
+
+// restError returns the error of a query answered with a status other than OK, wrapping
+// sentinelsdk.ErrNotFound for the Not Found status.
+func restError(method string, code int, body []byte) error {
+	// Read the message of the gRPC status returned by the gateway, if any.
+	var status struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil || status.Message == "" {
+		status.Message = strings.TrimSpace(string(body))
+	}
+
+	if code == http.StatusNotFound {
+		return fmt.Errorf("query %s: %w: %s", method, sentinelsdk.ErrNotFound, status.Message)
+	}
+
+	return fmt.Errorf("query %s: unexpected status %d: %s", method, code, status.Message)
+}
+
+// restRoute represents the route of a gRPC method on the REST gateway.
+type restRoute struct {
+	path     string       // path is the path template of the route, such as "/sentinel/nodes/{address}".
+	request  reflect.Type // request is the type of the request message.
+	response reflect.Type // response is the type of the response message.
+}
+
+// route returns the route of a gRPC method, such as "/sentinel.node.v2.QueryService/QueryNode",
+// read from the HTTP annotations of its service definition.
+func (b *RESTBackend) route(method string) (*restRoute, error) {
+	if v, ok := b.routes.Load(method); ok {
+		return v.(*restRoute), nil
+	}
+
+	route, err := newRESTRoute(method)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", method, err)
+	}
+
+	b.routes.Store(method, route)
+	return route, nil
+}
+
+// newRESTRoute reads the route of a gRPC method from the file descriptor of its request type.
+// The request type is found by the naming convention of the query services, such as
+// QueryNodeRequest for the QueryNode method, or QueryAccountRequest for the Account method.
+func newRESTRoute(method string) (*restRoute, error) {
+	// Split the method into its service, such as "sentinel.node.v2.QueryService", and its name.
+	service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid method %s", method)
+	}
+
+	pkg := service[:strings.LastIndex(service, ".")+1]
+	service = strings.TrimPrefix(service, pkg)
+
+	// Find the request type, whose generated code holds the file descriptor of the service.
+	var descriptor interface{ Descriptor() ([]byte, []int) }
+	for _, typeName := range []string{pkg + name + "Request", pkg + "Query" + name + "Request"} {
+		if t := gogoproto.MessageType(typeName); t != nil {
+			descriptor, _ = reflect.New(t.Elem()).Interface().(interface{ Descriptor() ([]byte, []int) })
+			if descriptor != nil {
+				break
+			}
+		}
+	}
+	if descriptor == nil {
+		return nil, fmt.Errorf("request type of method %s not found", method)
+	}
+
+	// Decompress and decode the file descriptor.
+	gz, _ := descriptor.Descriptor()
+
+	reader, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var file descriptorpb.FileDescriptorProto
+	if err := proto.Unmarshal(buf, &file); err != nil {
+		return nil, err
+	}
+
+	// Find the method in the services of the file, and read its GET route.
+	for _, s := range file.GetService() {
+		if s.GetName() != service {
+			continue
+		}
+
+		for _, m := range s.GetMethod() {
+			if m.GetName() != name {
+				continue
+			}
+
+			rule, _ := proto.GetExtension(m.GetOptions(), annotations.E_Http).(*annotations.HttpRule)
+			if rule.GetGet() == "" {
+				return nil, fmt.Errorf("method %s has no GET route", method)
+			}
+
+			request := gogoproto.MessageType(strings.TrimPrefix(m.GetInputType(), "."))
+			response := gogoproto.MessageType(strings.TrimPrefix(m.GetOutputType(), "."))
+			if request == nil || response == nil {
+				return nil, fmt.Errorf("message types of method %s not found", method)
+			}
+
+			return &restRoute{
+				path:     rule.GetGet(),
+				request:  request.Elem(),
+				response: response.Elem(),
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("method %s not found", method)
+}
@@ -0,0 +1,205 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	cosmossdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	sentinelhub "github.com/sentinel-official/hub/types"
+	nodetypes "github.com/sentinel-official/hub/x/node/types"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client/options"
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+func TestRESTBackend_Query(t *testing.T) {
+	cdc := codec.NewProtoCodec(sentinelsdk.NewInterfaceRegistry())
+
+	tests := []struct {
+		name       string
+		method     string
+		req        codec.ProtoMarshaler
+		opts       *options.QueryOptions
+		status     int
+		header     http.Header
+		body       string
+		wantPath   string
+		wantQuery  url.Values
+		wantHeight string
+		wantResp   codec.ProtoMarshaler
+		wantErr    error
+	}{
+		{
+			name:     "path parameter",
+			method:   "/sentinel.node.v2.QueryService/QueryNode",
+			req:      &nodetypes.QueryNodeRequest{Address: "sentnode1test"},
+			opts:     &options.QueryOptions{},
+			status:   http.StatusOK,
+			body:     `{"node": {"address": "sentnode1test", "remote_url": "https://node.example.com", "status": "active"}}`,
+			wantPath: "/sentinel/nodes/sentnode1test",
+			wantResp: &nodetypes.QueryNodeResponse{
+				Node: nodetypes.Node{
+					Address:   "sentnode1test",
+					RemoteURL: "https://node.example.com",
+					Status:    sentinelhub.StatusActive,
+				},
+			},
+		},
+		{
+			name:   "pagination query string",
+			method: "/sentinel.node.v2.QueryService/QueryNodes",
+			req: &nodetypes.QueryNodesRequest{
+				Status:     sentinelhub.StatusActive,
+				Pagination: &query.PageRequest{Key: []byte{0x01, 0x02}, Limit: 10, CountTotal: true},
+			},
+			opts:     &options.QueryOptions{},
+			status:   http.StatusOK,
+			body:     `{"nodes": [{"address": "sentnode1test", "status": "STATUS_INACTIVE"}], "pagination": {"next_key": "AQM=", "total": "5"}}`,
+			wantPath: "/sentinel/nodes",
+			wantQuery: url.Values{
+				"status":                 []string{"1"},
+				"pagination.key":         []string{"AQI="},
+				"pagination.limit":       []string{"10"},
+				"pagination.count_total": []string{"true"},
+			},
+			wantResp: &nodetypes.QueryNodesResponse{
+				Nodes:      []nodetypes.Node{{Address: "sentnode1test", Status: sentinelhub.StatusInactive}},
+				Pagination: &query.PageResponse{NextKey: []byte{0x01, 0x03}, Total: 5},
+			},
+		},
+		{
+			name:      "string zero",
+			method:    "/cosmos.bank.v1beta1.Query/Balance",
+			req:       &banktypes.QueryBalanceRequest{Address: "sent1test", Denom: "0"},
+			opts:      &options.QueryOptions{},
+			status:    http.StatusOK,
+			body:      `{"balance": {"denom": "0", "amount": "10"}}`,
+			wantPath:  "/cosmos/bank/v1beta1/balances/sent1test/by_denom",
+			wantQuery: url.Values{"denom": []string{"0"}},
+			wantResp:  &banktypes.QueryBalanceResponse{Balance: &cosmossdk.Coin{Denom: "0", Amount: cosmossdk.NewInt(10)}},
+		},
+		{
+			name:     "not found",
+			method:   "/sentinel.node.v2.QueryService/QueryNode",
+			req:      &nodetypes.QueryNodeRequest{Address: "sentnode1missing"},
+			opts:     &options.QueryOptions{},
+			status:   http.StatusNotFound,
+			body:     `{"code": 5, "message": "node does not exist"}`,
+			wantPath: "/sentinel/nodes/sentnode1missing",
+			wantErr:  sentinelsdk.ErrNotFound,
+		},
+		{
+			name:       "height header",
+			method:     "/sentinel.node.v2.QueryService/QueryNode",
+			req:        &nodetypes.QueryNodeRequest{Address: "sentnode1test"},
+			opts:       &options.QueryOptions{Height: 100},
+			status:     http.StatusOK,
+			header:     http.Header{"Grpc-Metadata-X-Cosmos-Block-Height": []string{"100"}},
+			body:       `{"node": {"address": "sentnode1test"}}`,
+			wantPath:   "/sentinel/nodes/sentnode1test",
+			wantHeight: "100",
+			wantResp:   &nodetypes.QueryNodeResponse{Node: nodetypes.Node{Address: "sentnode1test"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				for key, values := range tt.header {
+					w.Header()[key] = values
+				}
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			data, err := cdc.Marshal(tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := client.NewRESTBackend(srv.URL, cdc).Query(context.Background(), tt.method, data, tt.opts)
+
+			// Check the request received by the gateway.
+			if got == nil {
+				t.Fatal("Query() sent no request")
+			}
+			if got.URL.Path != tt.wantPath {
+				t.Errorf("Query() path = %s, want %s", got.URL.Path, tt.wantPath)
+			}
+			if q := got.URL.Query(); len(q) != 0 || len(tt.wantQuery) != 0 {
+				if !reflect.DeepEqual(q, tt.wantQuery) {
+					t.Errorf("Query() query = %v, want %v", q, tt.wantQuery)
+				}
+			}
+			if h := got.Header.Get("x-cosmos-block-height"); h != tt.wantHeight {
+				t.Errorf("Query() height header = %q, want %q", h, tt.wantHeight)
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Query() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+
+			// Check the response, encoded like those of the other backends.
+			if res.Height != tt.opts.Height {
+				t.Errorf("Query() height = %d, want %d", res.Height, tt.opts.Height)
+			}
+
+			resp := reflect.New(reflect.TypeOf(tt.wantResp).Elem()).Interface().(codec.ProtoMarshaler)
+			if err := cdc.Unmarshal(res.Value, resp); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(resp, tt.wantResp) {
+				t.Errorf("Query() response = %v, want %v", resp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func TestRESTBackend_Query_Prove(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer srv.Close()
+
+	cdc := codec.NewProtoCodec(sentinelsdk.NewInterfaceRegistry())
+	data, err := cdc.Marshal(&nodetypes.QueryNodeRequest{Address: "sentnode1test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.NewRESTBackend(srv.URL, cdc).Query(context.Background(), "/sentinel.node.v2.QueryService/QueryNode", data, &options.QueryOptions{Prove: true})
+	if err == nil {
+		t.Fatal("Query() error = nil, want an error for a proof over REST")
+	}
+	if hits != 0 {
+		t.Fatalf("Query() sent %d requests, want none", hits)
+	}
+}
+
+func TestRESTBackend_Query_UnknownMethod(t *testing.T) {
+	backend := client.NewRESTBackend("http://127.0.0.1:0", codec.NewProtoCodec(sentinelsdk.NewInterfaceRegistry()))
+
+	if _, err := backend.Query(context.Background(), "/test.Query/Unknown", nil, &options.QueryOptions{}); err == nil {
+		t.Fatal("Query() error = nil, want an error for a method without a route")
+	}
+}
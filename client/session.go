@@ -13,6 +13,7 @@ import (
 // Session queries and returns information about a specific session based on the provided session ID.
 // It uses gRPC to send a request to the "/sentinel.session.v2.QueryService/QuerySession" endpoint.
 // The result is a pointer to sessiontypes.Session and an error if the query fails.
+// It returns nil and a nil error if the session does not exist.
 func (c *Context) Session(ctx context.Context, id uint64, opts *options.QueryOptions) (res *sessiontypes.Session, err error) {
 	// Initialize variables for the query.
 	var (
@@ -23,8 +24,9 @@ func (c *Context) Session(ctx context.Context, id uint64, opts *options.QueryOpt
 		}
 	)
 
-	// Send a gRPC query using the provided context, method, request, response, and options.
-	if err := c.QueryGRPC(ctx, method, req, &resp, opts); err != nil {
+	// Send a gRPC query using the provided context, method, request, response, and options,
+	// returning nil if the session does not exist.
+	if ok, err := c.queryGRPCItem(ctx, method, req, &resp, opts); !ok {
 		return nil, err
 	}
 
@@ -41,7 +43,7 @@ func (c *Context) Sessions(ctx context.Context, opts *options.QueryOptions) (res
 		resp   sessiontypes.QuerySessionsResponse
 		method = "/sentinel.session.v2.QueryService/QuerySessions"
 		req    = &sessiontypes.QuerySessionsRequest{
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -65,7 +67,7 @@ func (c *Context) SessionsForAccount(ctx context.Context, accAddr cosmossdk.AccA
 		method = "/sentinel.session.v2.QueryService/QuerySessionsForAccount"
 		req    = &sessiontypes.QuerySessionsForAccountRequest{
 			Address:    accAddr.String(),
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -89,7 +91,7 @@ func (c *Context) SessionsForNode(ctx context.Context, nodeAddr sentinelhub.Node
 		method = "/sentinel.session.v2.QueryService/QuerySessionsForNode"
 		req    = &sessiontypes.QuerySessionsForNodeRequest{
 			Address:    nodeAddr.String(),
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -113,7 +115,7 @@ func (c *Context) SessionsForSubscription(ctx context.Context, id uint64, opts *
 		method = "/sentinel.session.v2.QueryService/QuerySessionsForSubscription"
 		req    = &sessiontypes.QuerySessionsForSubscriptionRequest{
 			Id:         id,
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -138,7 +140,7 @@ func (c *Context) SessionsForSubscriptionAllocation(ctx context.Context, id uint
 		req    = &sessiontypes.QuerySessionsForAllocationRequest{
 			Id:         id,
 			Address:    accAddr.String(),
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -13,6 +13,7 @@ import (
 // Subscription queries and returns information about a specific subscription based on the provided subscription ID.
 // It uses gRPC to send a request to the "/sentinel.subscription.v2.QueryService/QuerySubscription" endpoint.
 // The result is a subscriptiontypes.Subscription and an error if the query fails.
+// It returns nil and a nil error if the subscription does not exist.
 func (c *Context) Subscription(ctx context.Context, id uint64, opts *options.QueryOptions) (res subscriptiontypes.Subscription, err error) {
 	// Initialize variables for the query.
 	var (
@@ -23,8 +24,9 @@ func (c *Context) Subscription(ctx context.Context, id uint64, opts *options.Que
 		}
 	)
 
-	// Send a gRPC query using the provided context, method, request, response, and options.
-	if err := c.QueryGRPC(ctx, method, req, &resp, opts); err != nil {
+	// Send a gRPC query using the provided context, method, request, response, and options,
+	// returning nil if the subscription does not exist.
+	if ok, err := c.queryGRPCItem(ctx, method, req, &resp, opts); !ok {
 		return nil, err
 	}
 
@@ -45,7 +47,7 @@ func (c *Context) Subscriptions(ctx context.Context, opts *options.QueryOptions)
 		resp   subscriptiontypes.QuerySubscriptionsResponse
 		method = "/sentinel.subscription.v2.QueryService/QuerySubscriptions"
 		req    = &subscriptiontypes.QuerySubscriptionsRequest{
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -76,7 +78,7 @@ func (c *Context) SubscriptionsForAccount(ctx context.Context, accAddr cosmossdk
 		method = "/sentinel.subscription.v2.QueryService/QuerySubscriptionsForAccount"
 		req    = &subscriptiontypes.QuerySubscriptionsForAccountRequest{
 			Address:    accAddr.String(),
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -107,7 +109,7 @@ func (c *Context) SubscriptionsForNode(ctx context.Context, nodeAddr sentinelhub
 		method = "/sentinel.subscription.v2.QueryService/QuerySubscriptionsForNode"
 		req    = &subscriptiontypes.QuerySubscriptionsForNodeRequest{
 			Address:    nodeAddr.String(),
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -138,7 +140,7 @@ func (c *Context) SubscriptionsForPlan(ctx context.Context, id uint64, opts *opt
 		method = "/sentinel.subscription.v2.QueryService/QuerySubscriptionsForPlan"
 		req    = &subscriptiontypes.QuerySubscriptionsForPlanRequest{
 			Id:         id,
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -161,6 +163,7 @@ func (c *Context) SubscriptionsForPlan(ctx context.Context, id uint64, opts *opt
 // SubscriptionAllocation queries and returns information about a specific allocation within a subscription.
 // It uses gRPC to send a request to the "/sentinel.subscription.v2.QueryService/QueryAllocation" endpoint.
 // The result is a pointer to subscriptiontypes.Allocation and an error if the query fails.
+// It returns nil and a nil error if the allocation does not exist.
 func (c *Context) SubscriptionAllocation(ctx context.Context, id uint64, accAddr cosmossdk.AccAddress, opts *options.QueryOptions) (res *subscriptiontypes.Allocation, err error) {
 	// Initialize variables for the query.
 	var (
@@ -172,8 +175,9 @@ func (c *Context) SubscriptionAllocation(ctx context.Context, id uint64, accAddr
 		}
 	)
 
-	// Send a gRPC query using the provided context, method, request, response, and options.
-	if err := c.QueryGRPC(ctx, method, req, &resp, opts); err != nil {
+	// Send a gRPC query using the provided context, method, request, response, and options,
+	// returning nil if the allocation does not exist.
+	if ok, err := c.queryGRPCItem(ctx, method, req, &resp, opts); !ok {
 		return nil, err
 	}
 
@@ -191,7 +195,7 @@ func (c *Context) SubscriptionAllocations(ctx context.Context, id uint64, opts *
 		method = "/sentinel.subscription.v2.QueryService/QueryAllocations"
 		req    = &subscriptiontypes.QueryAllocationsRequest{
 			Id:         id,
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -207,6 +211,7 @@ func (c *Context) SubscriptionAllocations(ctx context.Context, id uint64, opts *
 // SubscriptionPayout queries and returns information about a specific payout within a subscription.
 // It uses gRPC to send a request to the "/sentinel.subscription.v2.QueryService/QueryPayout" endpoint.
 // The result is a pointer to subscriptiontypes.Payout and an error if the query fails.
+// It returns nil and a nil error if the payout does not exist.
 func (c *Context) SubscriptionPayout(ctx context.Context, id uint64, opts *options.QueryOptions) (res *subscriptiontypes.Payout, err error) {
 	// Initialize variables for the query.
 	var (
@@ -217,8 +222,9 @@ func (c *Context) SubscriptionPayout(ctx context.Context, id uint64, opts *optio
 		}
 	)
 
-	// Send a gRPC query using the provided context, method, request, response, and options.
-	if err := c.QueryGRPC(ctx, method, req, &resp, opts); err != nil {
+	// Send a gRPC query using the provided context, method, request, response, and options,
+	// returning nil if the payout does not exist.
+	if ok, err := c.queryGRPCItem(ctx, method, req, &resp, opts); !ok {
 		return nil, err
 	}
 
@@ -235,7 +241,7 @@ func (c *Context) SubscriptionPayouts(ctx context.Context, opts *options.QueryOp
 		resp   subscriptiontypes.QueryPayoutsResponse
 		method = "/sentinel.subscription.v2.QueryService/QueryPayouts"
 		req    = &subscriptiontypes.QueryPayoutsRequest{
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -259,7 +265,7 @@ func (c *Context) SubscriptionPayoutsForAccount(ctx context.Context, accAddr cos
 		method = "/sentinel.subscription.v2.QueryService/QueryPayoutsForAccount"
 		req    = &subscriptiontypes.QueryPayoutsForAccountRequest{
 			Address:    accAddr.String(),
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -283,7 +289,7 @@ func (c *Context) SubscriptionPayoutsForNode(ctx context.Context, nodeAddr senti
 		method = "/sentinel.subscription.v2.QueryService/QueryPayoutsForNode"
 		req    = &subscriptiontypes.QueryPayoutsForNodeRequest{
 			Address:    nodeAddr.String(),
-			Pagination: opts.PageRequest(),
+			Pagination: c.QueryOptions(opts).PageRequest(),
 		}
 	)
 
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client/options"
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// maxRetryDelay is the longest delay between two attempts to broadcast a transaction.
+const maxRetryDelay = 10 * time.Second
+
+// waitRetry waits for the given delay before a retry, returning the error of the context if it is done first.
+func waitRetry(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isUnsubmitted checks whether a broadcast error was raised before the transaction reached the RPC server,
+// such as a refused connection or a failed DNS lookup, so that the broadcast can be retried safely.
+// Errors such as an EOF or a timeout while waiting for the response may follow the submission.
+func isUnsubmitted(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// BroadcastTx broadcasts a signed and encoded transaction with configurable options.
+// The broadcast mode is either "sync", waiting for the transaction to pass CheckTx, or "async",
+// returning right after the transaction is submitted. It retries the broadcast according to the
+// specified maximum number of retries, only on errors raised before the transaction reaches the RPC
+// server, since a transaction already submitted could be submitted twice. The first retry waits for
+// the retry delay, doubled after each retry up to 10 seconds, and the retries stop once ctx is done.
+// It goes through the transaction interceptors of the Context.
+// The transaction is broadcast with the backend of the Context if set, once and without retries.
+// The options are merged over the default transaction options of the Context, and may be nil.
+func (c *Context) BroadcastTx(ctx context.Context, txBytes []byte, opts *options.TxOptions) (*coretypes.ResultBroadcastTx, error) {
+	// Merge the provided options over the default options.
+	opts = c.TxOptions(opts)
+
+	// Check the broadcast mode.
+	switch opts.BroadcastMode {
+	case "sync", "async":
+	default:
+		return nil, fmt.Errorf("unsupported broadcast mode %s", opts.BroadcastMode)
+	}
+
+	// Describe the broadcast for the interceptors.
+	tx := tmtypes.Tx(txBytes)
+	info := &TxInfo{
+		Endpoint: opts.RPCAddr,
+		Hash:     strings.ToUpper(fmt.Sprintf("%x", tx.Hash())),
+		Mode:     opts.BroadcastMode,
+	}
+
+	// Broadcast the transaction with the backend, if set.
+	if c.txBackend != nil {
+		info.Endpoint = c.txBackend.Endpoint()
+		return c.interceptTx(ctx, info, func(ctx context.Context) (*coretypes.ResultBroadcastTx, error) {
+			info.Attempts++
+			return c.txBackend.BroadcastTx(ctx, txBytes, opts.BroadcastMode)
+		})
+	}
+
+	// Get the RPC client from the merged options.
+	client, err := opts.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	// Select the broadcast function for the broadcast mode.
+	broadcast := client.BroadcastTxSync
+	if opts.BroadcastMode == "async" {
+		broadcast = client.BroadcastTxAsync
+	}
+
+	return c.interceptTx(ctx, info, func(ctx context.Context) (*coretypes.ResultBroadcastTx, error) {
+		// Retry the broadcast for the specified number of times, backing off between the attempts.
+		delay := opts.RetryDelay
+		for t := 0; t < opts.MaxRetries; t++ {
+			if t > 0 {
+				if err := waitRetry(ctx, delay); err != nil {
+					return nil, err
+				}
+
+				delay = min(2*delay, maxRetryDelay)
+			}
+
+			info.Attempts++
+
+			// Broadcast the transaction.
+			result, err := broadcast(ctx, tx)
+			if err != nil {
+				// Retry on the errors raised before the transaction is submitted, such as a refused connection.
+				if isUnsubmitted(err) {
+					continue
+				}
+
+				// Return other errors.
+				return nil, err
+			}
+
+			// Return the result of the successful broadcast.
+			return result, nil
+		}
+
+		// Return an error if the maximum retry limit is reached.
+		return nil, sentinelsdk.ErrMaxRetries
+	})
+}
@@ -0,0 +1,145 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client/options"
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// broadcast broadcasts a transaction to the given RPC address with three attempts and the given retry delay,
+// returning the result and the number of attempts reported to the interceptors.
+func broadcast(t *testing.T, ctx context.Context, rpcAddr string, delay time.Duration) (*coretypes.ResultBroadcastTx, int, error) {
+	t.Helper()
+
+	var attempts int
+	c := client.NewContext(codectypes.NewInterfaceRegistry()).
+		WithTxOptions(options.Tx().WithRPCAddr(rpcAddr).WithMaxRetries(3).WithRetryDelay(delay)).
+		WithTxInterceptors(
+			func(ctx context.Context, info *client.TxInfo, next client.TxInvoker) (*coretypes.ResultBroadcastTx, error) {
+				res, err := next(ctx)
+				attempts = info.Attempts
+				return res, err
+			},
+		)
+
+	res, err := c.BroadcastTx(ctx, []byte("tx"), nil)
+	return res, attempts, err
+}
+
+// refusedAddr returns the address of an RPC server refusing the connections.
+func refusedAddr(t *testing.T) string {
+	t.Helper()
+
+	// Reserve a port, and close it so the connections are refused.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	return "http://" + addr
+}
+
+func TestContext_BroadcastTx(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":{"code":0,"data":"","log":"","codespace":"","hash":"5D5F"}}`))
+	}))
+	defer server.Close()
+
+	res, attempts, err := broadcast(t, context.Background(), server.URL, time.Millisecond)
+	if err != nil {
+		t.Fatalf("BroadcastTx() error = %v", err)
+	}
+	if res.Hash.String() != "5D5F" {
+		t.Fatalf("BroadcastTx() hash = %s, want 5D5F", res.Hash)
+	}
+	if attempts != 1 || hits.Load() != 1 {
+		t.Fatalf("attempts = %d, hits = %d, want 1", attempts, hits.Load())
+	}
+}
+
+func TestContext_BroadcastTx_NoRetryAfterSubmission(t *testing.T) {
+	// Close the connection once the request is received, as a server failing after accepting the transaction.
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		_ = conn.Close()
+	}))
+	defer server.Close()
+
+	_, attempts, err := broadcast(t, context.Background(), server.URL, time.Millisecond)
+	if err == nil {
+		t.Fatal("BroadcastTx() error = nil, want the connection error")
+	}
+	if attempts != 1 || hits.Load() != 1 {
+		t.Fatalf("attempts = %d, hits = %d, want a single submission", attempts, hits.Load())
+	}
+}
+
+func TestContext_BroadcastTx_RetryBeforeSubmission(t *testing.T) {
+	// The retries wait for 100ms, then 200ms.
+	start := time.Now()
+
+	_, attempts, err := broadcast(t, context.Background(), refusedAddr(t), 100*time.Millisecond)
+	if !errors.Is(err, sentinelsdk.ErrMaxRetries) {
+		t.Fatalf("BroadcastTx() error = %v, want %v", err, sentinelsdk.ErrMaxRetries)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Fatalf("BroadcastTx() took %s, want at least 300ms of backoff", elapsed)
+	}
+}
+
+func TestContext_BroadcastTx_RetryCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The first retry would wait for a minute, unless the backoff stops with the context.
+	start := time.Now()
+
+	_, attempts, err := broadcast(t, ctx, refusedAddr(t), time.Minute)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("BroadcastTx() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", attempts)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("BroadcastTx() took %s, want to stop with the context", elapsed)
+	}
+}
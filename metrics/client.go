@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/client"
+)
+
+var _ prometheus.Collector = (*ClientMetrics)(nil)
+
+// ClientMetrics records Prometheus metrics of the queries and the transaction broadcasts of a client.Context:
+// their latency, their number of attempts and their errors, by method, endpoint and error class.
+// It is attached to a client.Context through its interceptors, and registered with a prometheus.Registerer.
+type ClientMetrics struct {
+	queryDurations *prometheus.HistogramVec // queryDurations observes the latency of the queries.
+	queryAttempts  *prometheus.HistogramVec // queryAttempts observes the number of attempts of the queries.
+	queryErrors    *prometheus.CounterVec   // queryErrors counts the failed queries.
+	txDurations    *prometheus.HistogramVec // txDurations observes the latency of the broadcasts.
+	txAttempts     *prometheus.HistogramVec // txAttempts observes the number of attempts of the broadcasts.
+	txErrors       *prometheus.CounterVec   // txErrors counts the failed broadcasts.
+}
+
+// NewClientMetrics creates a new set of client metrics, prefixed with the given namespace,
+// or with "sentinel" if the namespace is empty.
+func NewClientMetrics(namespace string) *ClientMetrics {
+	if namespace == "" {
+		namespace = "sentinel"
+	}
+
+	attemptBuckets := []float64{1, 2, 3, 5, 10, 20, 40, 60}
+
+	return &ClientMetrics{
+		queryDurations: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: "client",
+				Name:      "query_duration_seconds",
+				Help:      "Latency of the ABCI queries, including retries.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"method", "endpoint", "class"},
+		),
+		queryAttempts: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: "client",
+				Name:      "query_attempts",
+				Help:      "Attempts made to perform the ABCI queries.",
+				Buckets:   attemptBuckets,
+			},
+			[]string{"method", "endpoint"},
+		),
+		queryErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "client",
+				Name:      "query_errors_total",
+				Help:      "Failed ABCI queries.",
+			},
+			[]string{"method", "endpoint", "class"},
+		),
+		txDurations: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: "client",
+				Name:      "tx_broadcast_duration_seconds",
+				Help:      "Latency of the transaction broadcasts, including retries.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"mode", "endpoint", "class"},
+		),
+		txAttempts: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: "client",
+				Name:      "tx_broadcast_attempts",
+				Help:      "Attempts made to broadcast the transactions.",
+				Buckets:   attemptBuckets,
+			},
+			[]string{"mode", "endpoint"},
+		),
+		txErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "client",
+				Name:      "tx_broadcast_errors_total",
+				Help:      "Failed transaction broadcasts.",
+			},
+			[]string{"mode", "endpoint", "class"},
+		),
+	}
+}
+
+// Describe sends the descriptors of the metrics to the channel.
+func (m *ClientMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.queryDurations.Describe(ch)
+	m.queryAttempts.Describe(ch)
+	m.queryErrors.Describe(ch)
+	m.txDurations.Describe(ch)
+	m.txAttempts.Describe(ch)
+	m.txErrors.Describe(ch)
+}
+
+// Collect sends the metrics to the channel.
+func (m *ClientMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.queryDurations.Collect(ch)
+	m.queryAttempts.Collect(ch)
+	m.queryErrors.Collect(ch)
+	m.txDurations.Collect(ch)
+	m.txAttempts.Collect(ch)
+	m.txErrors.Collect(ch)
+}
+
+// QueryInterceptor returns a query interceptor recording the metrics of each query.
+func (m *ClientMetrics) QueryInterceptor() client.QueryInterceptor {
+	return func(ctx context.Context, info *client.QueryInfo, next client.QueryInvoker) (*abcitypes.ResponseQuery, error) {
+		start := time.Now()
+		res, err := next(ctx)
+
+		class := client.ErrorClass(err)
+		m.queryDurations.WithLabelValues(info.Path, info.Endpoint, class).Observe(time.Since(start).Seconds())
+		m.queryAttempts.WithLabelValues(info.Path, info.Endpoint).Observe(float64(info.Attempts))
+		if err != nil {
+			m.queryErrors.WithLabelValues(info.Path, info.Endpoint, class).Inc()
+		}
+
+		return res, err
+	}
+}
+
+// TxInterceptor returns a transaction interceptor recording the metrics of each broadcast.
+func (m *ClientMetrics) TxInterceptor() client.TxInterceptor {
+	return func(ctx context.Context, info *client.TxInfo, next client.TxInvoker) (*coretypes.ResultBroadcastTx, error) {
+		start := time.Now()
+		res, err := next(ctx)
+
+		class := client.ErrorClass(err)
+		m.txDurations.WithLabelValues(info.Mode, info.Endpoint, class).Observe(time.Since(start).Seconds())
+		m.txAttempts.WithLabelValues(info.Mode, info.Endpoint).Observe(float64(info.Attempts))
+		if err != nil {
+			m.txErrors.WithLabelValues(info.Mode, info.Endpoint, class).Inc()
+		}
+
+		return res, err
+	}
+}
@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+var _ prometheus.Collector = (*collector)(nil)
+
+// collector collects the peer count and the traffic statistics of a service when the metrics are scraped,
+// so that the reported values are always those of the service. For a sentinelsdk.ContextServerService,
+// it also collects the restarts and the crashes reported by its status.
+type collector struct {
+	service sentinelsdk.ServerService // service is the service the metrics are collected from.
+	timeout time.Duration             // timeout is the time allowed for collecting the peer statistics.
+
+	peers          *prometheus.Desc   // peers describes the number of peers.
+	peerUpload     *prometheus.Desc   // peerUpload describes the bytes uploaded by each peer.
+	peerDownload   *prometheus.Desc   // peerDownload describes the bytes downloaded by each peer.
+	upload         *prometheus.Desc   // upload describes the bytes uploaded by all the peers.
+	download       *prometheus.Desc   // download describes the bytes downloaded by all the peers.
+	restarts       *prometheus.Desc   // restarts describes the restarts of the service.
+	crashes        *prometheus.Desc   // crashes describes the exits of the service not requested by a stop.
+	scrapeFailures prometheus.Counter // scrapeFailures counts the failures to collect the peer statistics.
+}
+
+// newCollector creates a new collector of the given service.
+func newCollector(service sentinelsdk.ServerService, namespace string, labels prometheus.Labels, timeout time.Duration) *collector {
+	return &collector{
+		service: service,
+		timeout: timeout,
+		peers: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "peers"),
+			"Number of peers of the service.",
+			nil, labels,
+		),
+		peerUpload: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "peer", "upload_bytes_total"),
+			"Bytes uploaded by the peer.",
+			[]string{"peer"}, labels,
+		),
+		peerDownload: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "peer", "download_bytes_total"),
+			"Bytes downloaded by the peer.",
+			[]string{"peer"}, labels,
+		),
+		upload: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "upload_bytes"),
+			"Bytes uploaded by all the current peers, which drops when a peer is removed.",
+			nil, labels,
+		),
+		download: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "download_bytes"),
+			"Bytes downloaded by all the current peers, which drops when a peer is removed.",
+			nil, labels,
+		),
+		restarts: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "restarts_total"),
+			"Starts of the service following a previous start, including after a crash.",
+			nil, labels,
+		),
+		crashes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "crashes_total"),
+			"Exits of the service which were not requested by a stop.",
+			nil, labels,
+		),
+		scrapeFailures: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        "peer_statistics_failures_total",
+				Help:        "Failures to collect the peer statistics on a scrape.",
+				ConstLabels: labels,
+			},
+		),
+	}
+}
+
+// Describe sends the descriptors of the metrics to the channel.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.peers
+	ch <- c.peerUpload
+	ch <- c.peerDownload
+	ch <- c.upload
+	ch <- c.download
+	ch <- c.restarts
+	ch <- c.crashes
+	c.scrapeFailures.Describe(ch)
+}
+
+// Collect collects the peer count, the restarts, the crashes and the traffic statistics of the service,
+// and sends them to the channel. The traffic statistics are skipped, and the failure counted, if they
+// cannot be collected in time.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(c.peers, prometheus.GaugeValue, float64(c.service.PeerCount()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	// Collect the restarts and the crashes from the status of the service, if it reports one.
+	if service, ok := c.service.(sentinelsdk.ContextServerService); ok {
+		status := service.Status(ctx)
+		ch <- prometheus.MustNewConstMetric(c.restarts, prometheus.CounterValue, float64(status.Restarts))
+		ch <- prometheus.MustNewConstMetric(c.crashes, prometheus.CounterValue, float64(status.Crashes))
+	}
+
+	// Collect the traffic statistics of the peers, giving up after the timeout.
+	items, err := c.service.PeerStatistics(ctx)
+	if err != nil {
+		c.scrapeFailures.Inc()
+		c.scrapeFailures.Collect(ch)
+		return
+	}
+
+	// Report the traffic of each peer, and the total traffic of all the peers.
+	var upload, download int64
+	for _, item := range items {
+		ch <- prometheus.MustNewConstMetric(c.peerUpload, prometheus.CounterValue, float64(item.Upload), item.Key)
+		ch <- prometheus.MustNewConstMetric(c.peerDownload, prometheus.CounterValue, float64(item.Download), item.Key)
+
+		upload += item.Upload
+		download += item.Download
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.upload, prometheus.GaugeValue, float64(upload))
+	ch <- prometheus.MustNewConstMetric(c.download, prometheus.GaugeValue, float64(download))
+	c.scrapeFailures.Collect(ch)
+}
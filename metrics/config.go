@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPath represents the default HTTP path the metrics are served on.
+	DefaultPath = "/metrics"
+
+	// DefaultScrapeTimeout represents the default time allowed for collecting the peer statistics on a scrape.
+	DefaultScrapeTimeout = 5 * time.Second
+)
+
+// Config represents the configuration of the metrics exporter.
+type Config struct {
+	ListenAddr    string        // ListenAddr is the HTTP address the metrics are served on, such as "127.0.0.1:9100".
+	Namespace     string        // Namespace is the prefix of the metric names, empty for "sentinel".
+	Path          string        // Path is the HTTP path the metrics are served on, empty for DefaultPath.
+	ScrapeTimeout time.Duration // ScrapeTimeout is the time allowed for collecting the peer statistics, zero for DefaultScrapeTimeout.
+}
+
+// Validate checks whether the Config is valid.
+func (c *Config) Validate() error {
+	// Check if the listen address is a valid host and port.
+	if _, _, err := net.SplitHostPort(c.ListenAddr); err != nil {
+		return errors.New("invalid listen address")
+	}
+
+	// Check if the path is absolute.
+	if c.Path != "" && !strings.HasPrefix(c.Path, "/") {
+		return errors.New("path must start with /")
+	}
+
+	if c.ScrapeTimeout < 0 {
+		return errors.New("scrape timeout cannot be negative")
+	}
+
+	return nil
+}
+
+// namespace returns the prefix of the metric names.
+func (c *Config) namespace() string {
+	if c.Namespace == "" {
+		return "sentinel"
+	}
+
+	return c.Namespace
+}
+
+// path returns the HTTP path the metrics are served on.
+func (c *Config) path() string {
+	if c.Path == "" {
+		return DefaultPath
+	}
+
+	return c.Path
+}
+
+// scrapeTimeout returns the time allowed for collecting the peer statistics on a scrape.
+func (c *Config) scrapeTimeout() time.Duration {
+	if c.ScrapeTimeout == 0 {
+		return DefaultScrapeTimeout
+	}
+
+	return c.ScrapeTimeout
+}
@@ -0,0 +1,243 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+const (
+	// ReadHeaderTimeout represents the time allowed for reading the headers of a request to the metrics HTTP server.
+	ReadHeaderTimeout = 10 * time.Second
+
+	// ShutdownTimeout represents the time allowed for the metrics HTTP server to finish the requests in flight
+	// once its context is done.
+	ShutdownTimeout = 5 * time.Second
+)
+
+var (
+	_ sentinelsdk.ServerService        = (*Server)(nil)
+	_ sentinelsdk.ContextServerService = (*Server)(nil)
+)
+
+// Server wraps a sentinelsdk.ServerService, exposing Prometheus metrics of the service: the peer count,
+// the traffic of each peer and of all the peers, the latency and the errors of AddPeer and RemovePeer,
+// and the restarts and crashes reported by the status of the service, if it implements
+// sentinelsdk.ContextServerService. It can be used in place of the wrapped service, and implements
+// sentinelsdk.ContextServerService whether or not the wrapped service does.
+type Server struct {
+	sentinelsdk.ServerService
+
+	config   *Config              // config is the configuration of the metrics exporter.
+	registry *prometheus.Registry // registry holds the metrics of the service.
+
+	durations *prometheus.HistogramVec // durations observes the latency of the peer operations.
+	failures  *prometheus.CounterVec   // failures counts the failed peer operations.
+}
+
+// NewServer creates a new metrics exporter wrapping the given service.
+// The metrics are labelled with the type of the service.
+func NewServer(service sentinelsdk.ServerService, config *Config) *Server {
+	var (
+		namespace = config.namespace()
+		labels    = prometheus.Labels{"service": service.Type().String()}
+	)
+
+	s := &Server{
+		ServerService: service,
+		config:        config,
+		registry:      prometheus.NewRegistry(),
+		durations: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace:   namespace,
+				Name:        "peer_operation_duration_seconds",
+				Help:        "Latency of the peer operations.",
+				ConstLabels: labels,
+				Buckets:     prometheus.DefBuckets,
+			},
+			[]string{"operation"},
+		),
+		failures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        "peer_operation_errors_total",
+				Help:        "Failed peer operations.",
+				ConstLabels: labels,
+			},
+			[]string{"operation"},
+		),
+	}
+
+	// Register the metrics of the peer operations and the metrics collected on each scrape.
+	s.registry.MustRegister(
+		s.durations,
+		s.failures,
+		newCollector(service, namespace, labels, config.scrapeTimeout()),
+	)
+
+	return s
+}
+
+// observe records the latency of a peer operation started at the given time, and counts the operation
+// as failed if err is not nil.
+func (s *Server) observe(operation string, start time.Time, err error) {
+	s.durations.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		s.failures.WithLabelValues(operation).Inc()
+	}
+}
+
+// AddPeer adds a peer to the wrapped service, recording the latency and the error of the operation.
+func (s *Server) AddPeer(ctx context.Context, buf []byte) (res []byte, err error) {
+	start := time.Now()
+	defer func() { s.observe("add", start, err) }()
+
+	return s.ServerService.AddPeer(ctx, buf)
+}
+
+// RemovePeer removes a peer from the wrapped service, recording the latency and the error of the operation.
+func (s *Server) RemovePeer(ctx context.Context, buf []byte) (err error) {
+	start := time.Now()
+	defer func() { s.observe("remove", start, err) }()
+
+	return s.ServerService.RemovePeer(ctx, buf)
+}
+
+// contextService returns the wrapped service if it implements sentinelsdk.ContextServerService.
+func (s *Server) contextService() (sentinelsdk.ContextServerService, bool) {
+	v, ok := s.ServerService.(sentinelsdk.ContextServerService)
+	return v, ok
+}
+
+// Drain drains the wrapped service. It returns an error if the wrapped service cannot be drained.
+func (s *Server) Drain(ctx context.Context) error {
+	service, ok := s.contextService()
+	if !ok {
+		return fmt.Errorf("service %s does not support draining", s.Type())
+	}
+
+	return service.Drain(ctx)
+}
+
+// InitContext initializes the wrapped service, with the context-aware variant if it implements one.
+func (s *Server) InitContext(ctx context.Context) error {
+	if service, ok := s.contextService(); ok {
+		return service.InitContext(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return s.ServerService.Init()
+}
+
+// StartContext starts the wrapped service, with the context-aware variant if it implements one.
+func (s *Server) StartContext(ctx context.Context) error {
+	if service, ok := s.contextService(); ok {
+		return service.StartContext(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return s.ServerService.Start()
+}
+
+// Status returns the state of the wrapped service. If the wrapped service does not report its state,
+// only the peer count is set.
+func (s *Server) Status(ctx context.Context) *sentinelsdk.ServiceStatus {
+	if service, ok := s.contextService(); ok {
+		return service.Status(ctx)
+	}
+
+	return &sentinelsdk.ServiceStatus{
+		Peers: s.PeerCount(),
+	}
+}
+
+// StopContext stops the wrapped service, with the context-aware variant if it implements one.
+func (s *Server) StopContext(ctx context.Context) error {
+	if service, ok := s.contextService(); ok {
+		return service.StopContext(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return s.ServerService.Stop()
+}
+
+// WaitReady waits for the wrapped service to be ready. A service without a readiness check
+// is considered ready once started.
+func (s *Server) WaitReady(ctx context.Context) error {
+	if service, ok := s.contextService(); ok {
+		return service.WaitReady(ctx)
+	}
+
+	return ctx.Err()
+}
+
+// Registry returns the registry holding the metrics of the service, to gather them directly
+// or to serve them along with other metrics.
+func (s *Server) Registry() *prometheus.Registry {
+	return s.registry
+}
+
+// Handler returns an HTTP handler serving the metrics of the service in the Prometheus format.
+func (s *Server) Handler() http.Handler {
+	return promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{})
+}
+
+// ListenAndServe serves the metrics on the configured address and path until the context is done.
+// It returns nil once the HTTP server is shut down because the context is done.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	if err := s.config.Validate(); err != nil {
+		return err
+	}
+
+	// Listen on the configured address, so that errors such as an address in use are returned.
+	listener, err := net.Listen("tcp", s.config.ListenAddr)
+	if err != nil {
+		return err
+	}
+
+	// Serve the metrics on the configured path.
+	mux := http.NewServeMux()
+	mux.Handle(s.config.path(), s.Handler())
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: ReadHeaderTimeout,
+	}
+
+	// Shut down the HTTP server once the context is done, unless the HTTP server fails first.
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+			return
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/metrics"
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// fakeService is a sentinelsdk.ServerService reporting fixed values.
+type fakeService struct {
+	addErr     error
+	peers      int
+	statistics []*sentinelsdk.PeerStatistic
+	statsErr   error
+}
+
+func (s *fakeService) AddPeer(context.Context, []byte) ([]byte, error) { return nil, s.addErr }
+func (s *fakeService) HasPeer(context.Context, []byte) (bool, error)   { return false, nil }
+func (s *fakeService) Info() []byte                                    { return nil }
+func (s *fakeService) Init() error                                     { return nil }
+func (s *fakeService) PeerCount() int                                  { return s.peers }
+func (s *fakeService) RemovePeer(context.Context, []byte) error        { return nil }
+func (s *fakeService) Start() error                                    { return nil }
+func (s *fakeService) Stop() error                                     { return nil }
+func (s *fakeService) Type() sentinelsdk.ServiceType                   { return sentinelsdk.ServiceTypeV2Ray }
+
+func (s *fakeService) PeerStatistics(context.Context) ([]*sentinelsdk.PeerStatistic, error) {
+	return s.statistics, s.statsErr
+}
+
+// fakeContextService is a sentinelsdk.ContextServerService reporting a fixed status.
+type fakeContextService struct {
+	*fakeService
+	status *sentinelsdk.ServiceStatus
+}
+
+func (s *fakeContextService) Drain(context.Context) error        { return nil }
+func (s *fakeContextService) InitContext(context.Context) error  { return nil }
+func (s *fakeContextService) StartContext(context.Context) error { return nil }
+func (s *fakeContextService) StopContext(context.Context) error  { return nil }
+func (s *fakeContextService) WaitReady(context.Context) error    { return nil }
+func (s *fakeContextService) Status(context.Context) *sentinelsdk.ServiceStatus {
+	return s.status
+}
+
+func TestServer_Collect(t *testing.T) {
+	statistics := []*sentinelsdk.PeerStatistic{
+		{Key: "alice", Upload: 100, Download: 1000},
+		{Key: "bob", Upload: 20, Download: 200},
+	}
+
+	tests := []struct {
+		name    string
+		service sentinelsdk.ServerService
+		names   []string
+		want    string
+	}{
+		{
+			name:    "peers and traffic",
+			service: &fakeService{peers: 2, statistics: statistics},
+			names: []string{
+				"test_peers", "test_peer_upload_bytes_total", "test_peer_download_bytes_total",
+				"test_upload_bytes", "test_download_bytes", "test_peer_statistics_failures_total",
+			},
+			want: `
+# HELP test_download_bytes Bytes downloaded by all the current peers, which drops when a peer is removed.
+# TYPE test_download_bytes gauge
+test_download_bytes{service="v2ray"} 1200
+# HELP test_peer_download_bytes_total Bytes downloaded by the peer.
+# TYPE test_peer_download_bytes_total counter
+test_peer_download_bytes_total{peer="alice",service="v2ray"} 1000
+test_peer_download_bytes_total{peer="bob",service="v2ray"} 200
+# HELP test_peer_statistics_failures_total Failures to collect the peer statistics on a scrape.
+# TYPE test_peer_statistics_failures_total counter
+test_peer_statistics_failures_total{service="v2ray"} 0
+# HELP test_peer_upload_bytes_total Bytes uploaded by the peer.
+# TYPE test_peer_upload_bytes_total counter
+test_peer_upload_bytes_total{peer="alice",service="v2ray"} 100
+test_peer_upload_bytes_total{peer="bob",service="v2ray"} 20
+# HELP test_peers Number of peers of the service.
+# TYPE test_peers gauge
+test_peers{service="v2ray"} 2
+# HELP test_upload_bytes Bytes uploaded by all the current peers, which drops when a peer is removed.
+# TYPE test_upload_bytes gauge
+test_upload_bytes{service="v2ray"} 120
+`,
+		},
+		{
+			name:    "statistics failure",
+			service: &fakeService{peers: 1, statsErr: errors.New("api unreachable")},
+			names:   []string{"test_peers", "test_upload_bytes", "test_peer_statistics_failures_total"},
+			want: `
+# HELP test_peer_statistics_failures_total Failures to collect the peer statistics on a scrape.
+# TYPE test_peer_statistics_failures_total counter
+test_peer_statistics_failures_total{service="v2ray"} 1
+# HELP test_peers Number of peers of the service.
+# TYPE test_peers gauge
+test_peers{service="v2ray"} 1
+`,
+		},
+		{
+			name: "restarts and crashes from the status",
+			service: &fakeContextService{
+				fakeService: &fakeService{},
+				status:      &sentinelsdk.ServiceStatus{Restarts: 3, Crashes: 2},
+			},
+			names: []string{"test_restarts_total", "test_crashes_total"},
+			want: `
+# HELP test_crashes_total Exits of the service which were not requested by a stop.
+# TYPE test_crashes_total counter
+test_crashes_total{service="v2ray"} 2
+# HELP test_restarts_total Starts of the service following a previous start, including after a crash.
+# TYPE test_restarts_total counter
+test_restarts_total{service="v2ray"} 3
+`,
+		},
+		{
+			name:    "no status",
+			service: &fakeService{},
+			names:   []string{"test_restarts_total", "test_crashes_total"},
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := metrics.NewServer(tt.service, &metrics.Config{Namespace: "test"})
+
+			err := testutil.GatherAndCompare(server.Registry(), strings.NewReader(tt.want), tt.names...)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestServer_AddPeer(t *testing.T) {
+	service := &fakeService{addErr: errors.New("invalid request")}
+	server := metrics.NewServer(service, &metrics.Config{Namespace: "test"})
+
+	for i := 0; i < 2; i++ {
+		if _, err := server.AddPeer(context.Background(), nil); err == nil {
+			t.Fatal("AddPeer() error = nil, want the error of the service")
+		}
+	}
+
+	want := `
+# HELP test_peer_operation_errors_total Failed peer operations.
+# TYPE test_peer_operation_errors_total counter
+test_peer_operation_errors_total{operation="add",service="v2ray"} 2
+`
+	if err := testutil.GatherAndCompare(server.Registry(), strings.NewReader(want), "test_peer_operation_errors_total"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := testutil.CollectAndCount(server.Registry(), "test_peer_operation_duration_seconds"); got != 1 {
+		t.Fatalf("CollectAndCount() = %d, want 1 latency histogram", got)
+	}
+}
+
+func TestServer_ContextServerService(t *testing.T) {
+	// A service without a status reports only its peer count.
+	server := metrics.NewServer(&fakeService{peers: 4}, &metrics.Config{})
+	if status := server.Status(context.Background()); status.Peers != 4 || status.Running {
+		t.Fatalf("Status() = %+v, want only the peer count", status)
+	}
+	if err := server.Drain(context.Background()); err == nil {
+		t.Fatal("Drain() error = nil, want an error for a service without draining")
+	}
+
+	// A service with a status is forwarded to.
+	status := &sentinelsdk.ServiceStatus{Running: true, Restarts: 1}
+	server = metrics.NewServer(&fakeContextService{fakeService: &fakeService{}, status: status}, &metrics.Config{})
+	if got := server.Status(context.Background()); got != status {
+		t.Fatalf("Status() = %+v, want %+v", got, status)
+	}
+	if err := server.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+}
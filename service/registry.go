@@ -0,0 +1,113 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// ServerFactory creates a server-side service with the given home directory and configuration.
+// The configuration is the service-specific configuration, such as *v2ray.ServerConfig.
+type ServerFactory func(homeDir string, config interface{}) (types.ServerService, error)
+
+// ClientFactory creates a client-side service with the given home directory and configuration.
+// The configuration is the service-specific configuration, such as *v2ray.ClientConfig.
+type ClientFactory func(homeDir string, config interface{}) (types.ClientService, error)
+
+var (
+	mu      sync.RWMutex                                // mu protects the registered factories.
+	servers = make(map[types.ServiceType]ServerFactory) // servers maps service types to their server factories.
+	clients = make(map[types.ServiceType]ClientFactory) // clients maps service types to their client factories.
+)
+
+// RegisterServer registers the server factory of the given service type.
+// It is meant to be called from the init function of the package implementing the service,
+// and panics if the factory is nil or a factory is already registered for the service type.
+func RegisterServer(t types.ServiceType, factory ServerFactory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if factory == nil {
+		panic(fmt.Sprintf("nil server factory for service type %d", t))
+	}
+	if _, ok := servers[t]; ok {
+		panic(fmt.Sprintf("server factory already registered for service type %d", t))
+	}
+
+	servers[t] = factory
+}
+
+// RegisterClient registers the client factory of the given service type.
+// It is meant to be called from the init function of the package implementing the service,
+// and panics if the factory is nil or a factory is already registered for the service type.
+func RegisterClient(t types.ServiceType, factory ClientFactory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if factory == nil {
+		panic(fmt.Sprintf("nil client factory for service type %d", t))
+	}
+	if _, ok := clients[t]; ok {
+		panic(fmt.Sprintf("client factory already registered for service type %d", t))
+	}
+
+	clients[t] = factory
+}
+
+// NewServer creates the server-side service of the given type with the given home directory and configuration.
+// The package implementing the service must be imported to register its factory.
+func NewServer(t types.ServiceType, homeDir string, config interface{}) (types.ServerService, error) {
+	mu.RLock()
+	factory, ok := servers[t]
+	mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("no server registered for service type %d", t)
+	}
+
+	return factory(homeDir, config)
+}
+
+// NewClient creates the client-side service of the given type with the given home directory and configuration.
+// The package implementing the service must be imported to register its factory.
+func NewClient(t types.ServiceType, homeDir string, config interface{}) (types.ClientService, error) {
+	mu.RLock()
+	factory, ok := clients[t]
+	mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("no client registered for service type %d", t)
+	}
+
+	return factory(homeDir, config)
+}
+
+// ServerTypes returns the service types with a registered server factory, in ascending order.
+func ServerTypes() []types.ServiceType {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	items := make([]types.ServiceType, 0, len(servers))
+	for t := range servers {
+		items = append(items, t)
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i] < items[j] })
+	return items
+}
+
+// ClientTypes returns the service types with a registered client factory, in ascending order.
+func ClientTypes() []types.ServiceType {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	items := make([]types.ServiceType, 0, len(clients))
+	for t := range clients {
+		items = append(items, t)
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i] < items[j] })
+	return items
+}
@@ -0,0 +1,116 @@
+package service_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/service"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// The registry is global, so each test registers service types of its own, unknown to the other tests.
+
+// mustPanic fails the test if fn does not panic.
+func mustPanic(t *testing.T, name string, fn func()) {
+	t.Helper()
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s did not panic", name)
+		}
+	}()
+
+	fn()
+}
+
+func TestRegisterServer(t *testing.T) {
+	var (
+		gotHomeDir string
+		gotConfig  interface{}
+	)
+
+	service.RegisterServer(types.ServiceType(100), func(homeDir string, config interface{}) (types.ServerService, error) {
+		gotHomeDir, gotConfig = homeDir, config
+		return nil, nil
+	})
+
+	if _, err := service.NewServer(types.ServiceType(100), "/home", 42); err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	if gotHomeDir != "/home" || gotConfig != 42 {
+		t.Fatalf("NewServer() called the factory with %q, %v, want %q, %v", gotHomeDir, gotConfig, "/home", 42)
+	}
+
+	mustPanic(t, "RegisterServer() with a nil factory", func() {
+		service.RegisterServer(types.ServiceType(101), nil)
+	})
+	mustPanic(t, "RegisterServer() with a duplicate factory", func() {
+		service.RegisterServer(types.ServiceType(100), func(string, interface{}) (types.ServerService, error) {
+			return nil, nil
+		})
+	})
+
+	if _, err := service.NewServer(types.ServiceType(101), "/home", nil); err == nil {
+		t.Fatal("NewServer() error = nil, want an error for an unknown service type")
+	}
+}
+
+func TestRegisterClient(t *testing.T) {
+	var (
+		gotHomeDir string
+		gotConfig  interface{}
+	)
+
+	service.RegisterClient(types.ServiceType(100), func(homeDir string, config interface{}) (types.ClientService, error) {
+		gotHomeDir, gotConfig = homeDir, config
+		return nil, nil
+	})
+
+	if _, err := service.NewClient(types.ServiceType(100), "/home", 42); err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if gotHomeDir != "/home" || gotConfig != 42 {
+		t.Fatalf("NewClient() called the factory with %q, %v, want %q, %v", gotHomeDir, gotConfig, "/home", 42)
+	}
+
+	mustPanic(t, "RegisterClient() with a nil factory", func() {
+		service.RegisterClient(types.ServiceType(101), nil)
+	})
+	mustPanic(t, "RegisterClient() with a duplicate factory", func() {
+		service.RegisterClient(types.ServiceType(100), func(string, interface{}) (types.ClientService, error) {
+			return nil, nil
+		})
+	})
+
+	if _, err := service.NewClient(types.ServiceType(101), "/home", nil); err == nil {
+		t.Fatal("NewClient() error = nil, want an error for an unknown service type")
+	}
+}
+
+func TestServerTypes_ClientTypes(t *testing.T) {
+	// Register the service types out of order.
+	for _, item := range []types.ServiceType{203, 201, 202} {
+		service.RegisterServer(item, func(string, interface{}) (types.ServerService, error) { return nil, nil })
+		service.RegisterClient(item, func(string, interface{}) (types.ClientService, error) { return nil, nil })
+	}
+
+	// contains checks whether items holds want in order, ignoring the types registered by the other tests.
+	contains := func(items []types.ServiceType, want []types.ServiceType) bool {
+		var got []types.ServiceType
+		for _, item := range items {
+			if item > 200 {
+				got = append(got, item)
+			}
+		}
+
+		return reflect.DeepEqual(got, want)
+	}
+
+	want := []types.ServiceType{201, 202, 203}
+	if got := service.ServerTypes(); !contains(got, want) {
+		t.Errorf("ServerTypes() = %v, want %v in ascending order", got, want)
+	}
+	if got := service.ClientTypes(); !contains(got, want) {
+		t.Errorf("ClientTypes() = %v, want %v in ascending order", got, want)
+	}
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"errors"
+)
+
+// Sentinel errors returned by the clients and the services, to be tested with errors.Is.
+// The errors returned are usually wrapped with more context.
+var (
+	// ErrDraining is returned by ServerService.AddPeer while the service is draining.
+	ErrDraining = errors.New("service is draining")
+
+	// ErrInvalidPayload is returned when a binary message, such as a peer request, cannot be decoded.
+	ErrInvalidPayload = errors.New("invalid payload")
+
+	// ErrMaxRetries is returned when a request fails on every allowed attempt.
+	ErrMaxRetries = errors.New("reached max retry limit")
+
+	// ErrNilReply is returned when a query is answered without a reply.
+	ErrNilReply = errors.New("nil reply")
+
+	// ErrNotFound is returned when the requested item, such as a node or an inbound, does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrServiceRunning is returned when an operation requires the service to be stopped, such as starting it again.
+	ErrServiceRunning = errors.New("service is already running")
+
+	// ErrServiceNotRunning is returned when an operation requires the service to be running.
+	ErrServiceNotRunning = errors.New("service is not running")
+)
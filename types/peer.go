@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+)
+
+// PeerMessageVersion represents the current version of the binary encoding of PeerRequest and PeerResponse.
+//
+// Version 1 is laid out as follows:
+//
+//	version (1 byte) | service type (1 byte) | payload
+//
+// where the payload is encoded by the service of the given type.
+const PeerMessageVersion = 0x01
+
+// marshalPeerMessage encodes a peer message of the given service type with the given payload.
+func marshalPeerMessage(t ServiceType, payload []byte) ([]byte, error) {
+	if t.String() == "" {
+		return nil, fmt.Errorf("invalid service type %d", t)
+	}
+
+	buf := make([]byte, 0, 2+len(payload))
+	buf = append(buf, PeerMessageVersion, byte(t))
+
+	return append(buf, payload...), nil
+}
+
+// unmarshalPeerMessage decodes a peer message, returning its service type and payload.
+func unmarshalPeerMessage(buf []byte) (ServiceType, []byte, error) {
+	if len(buf) < 2 {
+		return ServiceTypeUnspecified, nil, fmt.Errorf("%w: invalid peer message length; expected at least 2, got %d", ErrInvalidPayload, len(buf))
+	}
+	if buf[0] != PeerMessageVersion {
+		return ServiceTypeUnspecified, nil, fmt.Errorf("%w: unsupported peer message version %d", ErrInvalidPayload, buf[0])
+	}
+
+	return ServiceType(buf[1]), buf[2:], nil
+}
+
+// PeerRequest represents a request to add, check or remove a peer of a server service.
+// The payload is specific to the service type, such as a public key for WireGuard, or a proxy
+// and a UUID for V2Ray.
+type PeerRequest struct {
+	Type    ServiceType // Type is the type of the service the request is meant for.
+	Payload []byte      // Payload is the service-specific request.
+}
+
+// MarshalBinary encodes the PeerRequest in the binary format of the current PeerMessageVersion.
+func (r *PeerRequest) MarshalBinary() ([]byte, error) {
+	return marshalPeerMessage(r.Type, r.Payload)
+}
+
+// UnmarshalBinary decodes the PeerRequest from the binary format.
+func (r *PeerRequest) UnmarshalBinary(buf []byte) (err error) {
+	r.Type, r.Payload, err = unmarshalPeerMessage(buf)
+	return err
+}
+
+// Validate checks whether the PeerRequest is meant for a service of the given type.
+func (r *PeerRequest) Validate(t ServiceType) error {
+	if r.Type != t {
+		return fmt.Errorf("%w: invalid service type; expected %s, got %d", ErrInvalidPayload, t, r.Type)
+	}
+
+	return nil
+}
+
+// PeerResponse represents the response of a server service to a request adding a peer.
+// The payload is specific to the service type, and carries what the client needs to connect,
+// such as the addresses or the ports assigned to the peer.
+type PeerResponse struct {
+	Type    ServiceType // Type is the type of the service which added the peer.
+	Payload []byte      // Payload is the service-specific response.
+}
+
+// MarshalBinary encodes the PeerResponse in the binary format of the current PeerMessageVersion.
+func (r *PeerResponse) MarshalBinary() ([]byte, error) {
+	return marshalPeerMessage(r.Type, r.Payload)
+}
+
+// UnmarshalBinary decodes the PeerResponse from the binary format.
+func (r *PeerResponse) UnmarshalBinary(buf []byte) (err error) {
+	r.Type, r.Payload, err = unmarshalPeerMessage(buf)
+	return err
+}
+
+// Validate checks whether the PeerResponse comes from a service of the given type.
+func (r *PeerResponse) Validate(t ServiceType) error {
+	if r.Type != t {
+		return fmt.Errorf("%w: invalid service type; expected %s, got %d", ErrInvalidPayload, t, r.Type)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"context"
+	"time"
 )
 
 // ServiceType represents different types of network services supported by the system.
@@ -47,6 +48,21 @@ type PeerStatistic struct {
 	Upload   int64  `json:"upload"`
 }
 
+// ServiceStatus represents the state of a service.
+// Restarts counts the starts following a previous start, and Crashes counts the exits which were
+// not requested by Stop, since the service was created.
+type ServiceStatus struct {
+	APIReachable bool          `json:"api_reachable"`
+	Crashes      int           `json:"crashes"`
+	Draining     bool          `json:"draining"`
+	LastError    string        `json:"last_error"`
+	PID          int           `json:"pid"`
+	Peers        int           `json:"peers"`
+	Restarts     int           `json:"restarts"`
+	Running      bool          `json:"running"`
+	Uptime       time.Duration `json:"uptime"`
+}
+
 // ClientService defines the interface for client-side network services.
 type ClientService interface {
 	Down() error
@@ -61,6 +77,8 @@ type ClientService interface {
 }
 
 // ServerService defines the interface for server-side network services.
+// AddPeer, HasPeer and RemovePeer take a PeerRequest, and AddPeer returns a PeerResponse,
+// encoded in their binary format.
 type ServerService interface {
 	AddPeer(context.Context, []byte) ([]byte, error)
 	HasPeer(context.Context, []byte) (bool, error)
@@ -73,3 +91,18 @@ type ServerService interface {
 	Stop() error
 	Type() ServiceType
 }
+
+// ContextServerService defines the interface for server-side network services with context-aware
+// variants of Init, Start and Stop, along with draining, status and readiness.
+// Callers detect it with a type assertion on a ServerService.
+// Drain rejects new peers with ErrDraining, and stops the service once the remaining peers are removed
+// or the context is done. WaitReady blocks until the service can add peers after Start.
+type ContextServerService interface {
+	ServerService
+	Drain(context.Context) error
+	InitContext(context.Context) error
+	StartContext(context.Context) error
+	Status(context.Context) *ServiceStatus
+	StopContext(context.Context) error
+	WaitReady(context.Context) error
+}
@@ -0,0 +1,172 @@
+package v2ray
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	statscommand "github.com/v2fly/v2ray-core/v5/app/stats/command"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+// Client represents the V2Ray client instance.
+// It serves a local SOCKS proxy whose traffic is tunneled to the server through the configured outbound.
+type Client struct {
+	config  *ClientConfig // config is the configuration of the V2Ray client.
+	homeDir string        // homeDir is the home directory of the V2Ray client.
+	process *process      // process runs the V2Ray core of the client.
+}
+
+// NewClient creates a new instance of the V2Ray client.
+// The client runs the V2Ray core in the mode given by the configuration.
+func NewClient(homeDir string, config *ClientConfig) *Client {
+	c := &Client{
+		config:  config,
+		homeDir: homeDir,
+	}
+
+	// Determine the mode and the API port from the configuration, defaulting to the exec mode.
+	var (
+		mode    = ModeExec
+		apiPort uint16
+	)
+
+	if config != nil {
+		if config.Mode.IsEmbedded() {
+			mode = ModeEmbedded
+		}
+
+		apiPort = config.APIPort
+	}
+
+	c.process = newProcess(mode, c.configFilePath(), apiPort)
+	return c
+}
+
+// configFilePath returns the full path of the V2Ray client's configuration file.
+func (c *Client) configFilePath() string {
+	return filepath.Join(c.homeDir, ClientConfigFilename)
+}
+
+// Down stops the V2Ray client.
+func (c *Client) Down() error {
+	return c.process.stop(context.Background())
+}
+
+// Info returns information about the server the V2Ray client connects to,
+// encoded in the binary format of types.Info. It returns nil if the client is not configured.
+func (c *Client) Info() []byte {
+	// Check if the configuration is nil.
+	if c.config == nil || c.config.Outbound == nil {
+		return nil
+	}
+
+	// Encode the information about the server inbound the client connects to.
+	info := &types.Info{
+		Inbounds: []*types.InboundInfo{
+			c.config.Outbound.Info(),
+		},
+	}
+
+	buf, err := info.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+
+	return buf
+}
+
+// IsUp checks whether the V2Ray client is running.
+func (c *Client) IsUp() bool {
+	return c.process.isRunning()
+}
+
+// PostDown runs after the V2Ray client is stopped. It does nothing.
+func (c *Client) PostDown() error {
+	return nil
+}
+
+// PostUp runs after the V2Ray client is started. It does nothing.
+func (c *Client) PostUp() error {
+	return nil
+}
+
+// PreDown runs before the V2Ray client is stopped. It does nothing.
+func (c *Client) PreDown() error {
+	return nil
+}
+
+// PreUp prepares the V2Ray client by building its core configuration.
+// In exec mode, the configuration is written to the configuration file.
+func (c *Client) PreUp() error {
+	// Check if the configuration is nil.
+	if c.config == nil {
+		return errors.New("nil config")
+	}
+
+	// Build the V2Ray core configuration from the client configuration.
+	config, err := c.config.Build()
+	if err != nil {
+		return err
+	}
+
+	// Prepare the process to run the configuration.
+	return c.process.init(config)
+}
+
+// Statistics returns the upload and download traffic of the V2Ray client in bytes.
+func (c *Client) Statistics() (int64, int64, error) {
+	// Check if the configuration is nil.
+	if c.config == nil || c.config.Outbound == nil {
+		return 0, 0, errors.New("nil config")
+	}
+
+	// Establish a client connection to the stats service.
+	conn, client, err := c.process.statsServiceClient(context.Background())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Ensure the connection is closed when done.
+	defer func() {
+		if err = conn.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	// Define a function to get the value of a traffic stat of the outbound.
+	tag := c.config.Outbound.Tag()
+	stat := func(direction string) (int64, error) {
+		req := &statscommand.GetStatsRequest{
+			Reset_: false,
+			Name:   fmt.Sprintf("outbound>>>%s>>>traffic>>>%s", tag, direction),
+		}
+
+		res, err := client.GetStats(context.Background(), req)
+		if err != nil {
+			return 0, err
+		}
+
+		return res.GetStat().GetValue(), nil
+	}
+
+	// Get the uplink and downlink traffic stats of the outbound.
+	upload, err := stat("uplink")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	download, err := stat("downlink")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return upload, download, nil
+}
+
+// Up starts the V2Ray client.
+func (c *Client) Up() error {
+	return c.process.start(context.Background())
+}
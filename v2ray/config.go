@@ -0,0 +1,445 @@
+package v2ray
+
+import (
+	"errors"
+	"fmt"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/app/commander"
+	"github.com/v2fly/v2ray-core/v5/app/dispatcher"
+	applog "github.com/v2fly/v2ray-core/v5/app/log"
+	"github.com/v2fly/v2ray-core/v5/app/policy"
+	"github.com/v2fly/v2ray-core/v5/app/proxyman"
+	proxymancommand "github.com/v2fly/v2ray-core/v5/app/proxyman/command"
+	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/app/stats"
+	statscommand "github.com/v2fly/v2ray-core/v5/app/stats/command"
+	commonlog "github.com/v2fly/v2ray-core/v5/common/log"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/serial"
+	"github.com/v2fly/v2ray-core/v5/proxy/blackhole"
+	"github.com/v2fly/v2ray-core/v5/proxy/dokodemo"
+	"github.com/v2fly/v2ray-core/v5/proxy/freedom"
+	"github.com/v2fly/v2ray-core/v5/proxy/socks"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+const (
+	// APITag represents the tag of the inbound serving the V2Ray API.
+	APITag = "api"
+
+	// APIPort represents the local port the V2Ray API of the server listens on.
+	APIPort = 23
+
+	// SOCKSTag represents the tag of the client inbound serving the local SOCKS proxy.
+	SOCKSTag = "socks"
+
+	// BlockTag represents the tag of the server outbound dropping the traffic blocked by routing rules.
+	BlockTag = "block"
+)
+
+// ServerConfig represents the configuration of the V2Ray server.
+type ServerConfig struct {
+	Inbounds []*types.InboundConfig // Inbounds is the list of inbounds served to peers.
+	Mode     Mode                   // Mode is the way the V2Ray core is run.
+
+	// Policies is the list of policy levels peers can be assigned to. Level 0 is always available
+	// and defaults to the V2Ray defaults with traffic statistics enabled.
+	Policies []*types.PolicyConfig
+	// Routing holds the rules restricting the destinations peers can reach, or nil for no restrictions
+	// besides the node itself, which is never reachable; see types.LoopbackRules.
+	Routing *types.RoutingConfig
+}
+
+// Validate checks whether the ServerConfig is valid.
+func (c *ServerConfig) Validate() error {
+	if len(c.Inbounds) == 0 {
+		return errors.New("inbounds cannot be empty")
+	}
+
+	// Ensure every policy is valid and has a unique level.
+	levels := make(map[uint32]bool)
+	for _, item := range c.Policies {
+		if err := item.Validate(); err != nil {
+			return err
+		}
+		if levels[item.Level] {
+			return fmt.Errorf("duplicate policy level %d", item.Level)
+		}
+
+		levels[item.Level] = true
+	}
+
+	// Ensure the routing rules are valid and the inbounds detect BitTorrent traffic if it is blocked.
+	if c.Routing != nil {
+		if err := c.Routing.Validate(); err != nil {
+			return err
+		}
+
+		for _, inbound := range c.Inbounds {
+			if c.Routing.BlockBitTorrent && !inbound.Sniffing {
+				return fmt.Errorf("inbound %s requires sniffing to block bittorrent", inbound.Tag())
+			}
+		}
+	}
+
+	// Ensure every inbound is valid and has a unique tag and port range.
+	tags := make(map[string]bool)
+	for i, inbound := range c.Inbounds {
+		if err := inbound.Validate(); err != nil {
+			return err
+		}
+		if tags[inbound.Tag()] {
+			return fmt.Errorf("duplicate inbound tag %s", inbound.Tag())
+		}
+
+		tags[inbound.Tag()] = true
+
+		// A domain socket inbound listens on the socket path instead of a port.
+		if inbound.Transport == types.TransportDomainSocket {
+			continue
+		}
+
+		first, last := inbound.PortRange()
+		if first <= APIPort && APIPort <= last {
+			return fmt.Errorf("inbound %s overlaps with the api port %d", inbound.Tag(), APIPort)
+		}
+
+		for _, other := range c.Inbounds[:i] {
+			if other.Transport == types.TransportDomainSocket {
+				continue
+			}
+
+			otherFirst, otherLast := other.PortRange()
+			if first <= otherLast && otherFirst <= last {
+				return fmt.Errorf("inbound %s overlaps with the ports of inbound %s", inbound.Tag(), other.Tag())
+			}
+		}
+	}
+
+	return nil
+}
+
+// Inbound returns the inbound with the given tag, or nil if it does not exist.
+func (c *ServerConfig) Inbound(tag string) *types.InboundConfig {
+	for _, inbound := range c.Inbounds {
+		if inbound.Tag() == tag {
+			return inbound
+		}
+	}
+
+	return nil
+}
+
+// ProxyInbounds returns the inbounds serving the given proxy.
+func (c *ServerConfig) ProxyInbounds(proxy types.Proxy) (items []*types.InboundConfig) {
+	for _, inbound := range c.Inbounds {
+		if inbound.Proxy == proxy {
+			items = append(items, inbound)
+		}
+	}
+
+	return items
+}
+
+// HasLevel checks whether peers can be assigned to the given policy level.
+func (c *ServerConfig) HasLevel(level uint32) bool {
+	if level == 0 {
+		return true
+	}
+
+	for _, item := range c.Policies {
+		if item.Level == level {
+			return true
+		}
+	}
+
+	return false
+}
+
+// policies builds the v2ray policies of the levels peers can be assigned to.
+func (c *ServerConfig) policies() map[uint32]*policy.Policy {
+	items := map[uint32]*policy.Policy{
+		0: (&types.PolicyConfig{}).Policy(),
+	}
+
+	for _, item := range c.Policies {
+		items[item.Level] = item.Policy()
+	}
+
+	return items
+}
+
+// Info returns the information about the server published to clients.
+// Inbounds listening on a domain socket are not reachable by clients, and are left out.
+func (c *ServerConfig) Info() *types.Info {
+	info := &types.Info{}
+	for _, inbound := range c.Inbounds {
+		if inbound.Transport == types.TransportDomainSocket {
+			continue
+		}
+
+		info.Inbounds = append(info.Inbounds, inbound.Info())
+	}
+
+	return info
+}
+
+// apiInbound builds the inbound handler configuration serving the V2Ray API on the given local port.
+func apiInbound(port uint16) *core.InboundHandlerConfig {
+	return &core.InboundHandlerConfig{
+		Tag: APITag,
+		ReceiverSettings: serial.ToTypedMessage(
+			&proxyman.ReceiverConfig{
+				PortRange: net.SinglePortRange(net.Port(port)),
+				Listen:    net.NewIPOrDomain(net.LocalHostIP),
+			},
+		),
+		ProxySettings: serial.ToTypedMessage(
+			&dokodemo.Config{
+				Address: net.NewIPOrDomain(net.LocalHostIP),
+				Networks: []net.Network{
+					net.Network_TCP,
+				},
+			},
+		),
+	}
+}
+
+// apiApp builds the commander application serving the handler and stats services on the API inbound.
+func apiApp() *anypb.Any {
+	return serial.ToTypedMessage(
+		&commander.Config{
+			Tag: APITag,
+			Service: []*anypb.Any{
+				serial.ToTypedMessage(&proxymancommand.Config{}),
+				serial.ToTypedMessage(&statscommand.Config{}),
+			},
+		},
+	)
+}
+
+// logApp builds the log application of the V2Ray core, recording the access log and the messages
+// of the Info severity or higher.
+// When the core runs as a separate process, the logs are written to its standard output, where they are
+// captured line by line. An in-process core writes nothing, since its log messages are followed directly.
+func logApp(mode Mode) *anypb.Any {
+	logType := applog.LogType_Console
+	if mode.IsEmbedded() {
+		logType = applog.LogType_None
+	}
+
+	return serial.ToTypedMessage(
+		&applog.Config{
+			Error: &applog.LogSpecification{
+				Type:  logType,
+				Level: commonlog.Severity_Info,
+			},
+			Access: &applog.LogSpecification{
+				Type: logType,
+			},
+		},
+	)
+}
+
+// apiRoutingRule builds the routing rule sending the traffic of the API inbound to the commander.
+func apiRoutingRule() *router.RoutingRule {
+	return &router.RoutingRule{
+		InboundTag: []string{APITag},
+		TargetTag: &router.RoutingRule_Tag{
+			Tag: APITag,
+		},
+	}
+}
+
+// Build builds the V2Ray core configuration of the server.
+func (c *ServerConfig) Build() (*core.Config, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Define the applications required for peer management and statistics:
+	// - Log: Records the access log and the messages of the V2Ray core.
+	// - Policy: Defines the connection handling and the traffic statistics of each policy level.
+	config := &core.Config{
+		App: []*anypb.Any{
+			logApp(c.Mode),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&stats.Config{}),
+			serial.ToTypedMessage(
+				&policy.Config{
+					Level: c.policies(),
+				},
+			),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	// Serve the V2Ray API when the core runs as a separate process.
+	// An in-process core is managed directly through its feature managers.
+	routing := &router.Config{}
+	if !c.Mode.IsEmbedded() {
+		config.App = append(config.App, apiApp())
+		config.Inbound = append(config.Inbound, apiInbound(APIPort))
+		routing.Rule = append(routing.Rule, apiRoutingRule())
+	}
+
+	// Send the traffic blocked by the routing rules to an outbound dropping it. The rules follow
+	// the API rule, so the API inbound itself is still routed to the commander.
+	// Peers can never reach the node itself, such as the API port, whatever the configured rules.
+	rules, err := types.LoopbackRules(BlockTag)
+	if err != nil {
+		return nil, err
+	}
+
+	routing.Rule = append(routing.Rule, rules...)
+
+	if c.Routing != nil {
+		rules, err := c.Routing.Rules(BlockTag)
+		if err != nil {
+			return nil, err
+		}
+
+		routing.Rule = append(routing.Rule, rules...)
+	}
+
+	// Resolve the domains not matched by the rules, so a domain cannot be used to reach a blocked address.
+	routing.DomainStrategy = router.DomainStrategy_IpIfNonMatch
+
+	config.App = append(config.App, serial.ToTypedMessage(routing))
+	config.Outbound = append(config.Outbound, &core.OutboundHandlerConfig{
+		Tag:           BlockTag,
+		ProxySettings: serial.ToTypedMessage(&blackhole.Config{}),
+	})
+
+	// Append the inbounds served to peers. The inbounds of single-user proxies
+	// are created per peer at runtime with AddPeer.
+	for _, inbound := range c.Inbounds {
+		if !inbound.Proxy.IsMultiUser() {
+			continue
+		}
+
+		handler, err := inbound.HandlerConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		config.Inbound = append(config.Inbound, handler)
+	}
+
+	return config, nil
+}
+
+// ClientConfig represents the configuration of the V2Ray client.
+type ClientConfig struct {
+	APIPort   uint16                // APIPort is the local port the V2Ray API listens on (exec mode only).
+	Mode      Mode                  // Mode is the way the V2Ray core is run.
+	Outbound  *types.OutboundConfig // Outbound is the outbound connecting to the server.
+	SOCKSPort uint16                // SOCKSPort is the local port of the SOCKS proxy served to applications.
+}
+
+// Validate checks whether the ClientConfig is valid.
+func (c *ClientConfig) Validate() error {
+	if c.Outbound == nil {
+		return errors.New("outbound cannot be nil")
+	}
+	if err := c.Outbound.Validate(); err != nil {
+		return err
+	}
+	if c.SOCKSPort == 0 {
+		return errors.New("socks port cannot be zero")
+	}
+	if !c.Mode.IsEmbedded() {
+		if c.APIPort == 0 {
+			return errors.New("api port cannot be zero")
+		}
+		if c.APIPort == c.SOCKSPort {
+			return errors.New("api port and socks port cannot be the same")
+		}
+	}
+
+	return nil
+}
+
+// Build builds the V2Ray core configuration of the client.
+func (c *ClientConfig) Build() (*core.Config, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Build the outbound connecting to the server.
+	outbound, err := c.Outbound.HandlerConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Define the applications required for traffic statistics:
+	// - Log: Records the access log and the messages of the V2Ray core.
+	// - Policy: Enables outbound traffic statistics.
+	config := &core.Config{
+		App: []*anypb.Any{
+			logApp(c.Mode),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&stats.Config{}),
+			serial.ToTypedMessage(
+				&policy.Config{
+					System: &policy.SystemPolicy{
+						Stats: &policy.SystemPolicy_Stats{
+							OutboundUplink:   true,
+							OutboundDownlink: true,
+						},
+					},
+				},
+			),
+		},
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				Tag: SOCKSTag,
+				ReceiverSettings: serial.ToTypedMessage(
+					&proxyman.ReceiverConfig{
+						PortRange: net.SinglePortRange(net.Port(c.SOCKSPort)),
+						Listen:    net.NewIPOrDomain(net.LocalHostIP),
+					},
+				),
+				ProxySettings: serial.ToTypedMessage(
+					&socks.ServerConfig{
+						AuthType:   socks.AuthType_NO_AUTH,
+						Address:    net.NewIPOrDomain(net.LocalHostIP),
+						UdpEnabled: true,
+					},
+				),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			outbound,
+		},
+	}
+
+	// Serve the V2Ray API when the core runs as a separate process.
+	if !c.Mode.IsEmbedded() {
+		config.App = append(
+			config.App,
+			apiApp(),
+			serial.ToTypedMessage(
+				&router.Config{
+					Rule: []*router.RoutingRule{
+						apiRoutingRule(),
+					},
+				},
+			),
+		)
+		config.Inbound = append(config.Inbound, apiInbound(c.APIPort))
+	}
+
+	return config, nil
+}
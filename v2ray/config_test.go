@@ -0,0 +1,105 @@
+package v2ray_test
+
+import (
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/app/router"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+// routingConfig returns the router configuration of the given core configuration built by ServerConfig.Build.
+func routingConfig(t *testing.T, config *v2ray.ServerConfig) *router.Config {
+	t.Helper()
+
+	built, err := config.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	for _, app := range built.App {
+		var routing router.Config
+		if app.MessageIs(&routing) {
+			if err := app.UnmarshalTo(&routing); err != nil {
+				t.Fatalf("UnmarshalTo() error = %v", err)
+			}
+
+			return &routing
+		}
+	}
+
+	t.Fatal("Build() has no router configuration")
+	return nil
+}
+
+func TestServerConfig_Build_Routing(t *testing.T) {
+	inbounds := []*types.InboundConfig{
+		{Port: 8080, Proxy: types.ProxyVMess, Transport: types.TransportTCP},
+	}
+
+	loopback, err := types.LoopbackRules(v2ray.BlockTag)
+	if err != nil {
+		t.Fatalf("LoopbackRules() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		config    *v2ray.ServerConfig
+		wantAPI   bool
+		wantRules int
+	}{
+		{
+			name:      "exec mode without routing",
+			config:    &v2ray.ServerConfig{Inbounds: inbounds},
+			wantAPI:   true,
+			wantRules: 1 + len(loopback),
+		},
+		{
+			name:      "embedded mode without routing",
+			config:    &v2ray.ServerConfig{Inbounds: inbounds, Mode: v2ray.ModeEmbedded},
+			wantRules: len(loopback),
+		},
+		{
+			name: "exec mode with routing",
+			config: &v2ray.ServerConfig{
+				Inbounds: inbounds,
+				Routing:  &types.RoutingConfig{BlockPorts: []uint16{25}, BlockDomains: []string{"example.org"}},
+			},
+			wantAPI:   true,
+			wantRules: 1 + len(loopback) + 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routing := routingConfig(t, tt.config)
+			if len(routing.Rule) != tt.wantRules {
+				t.Fatalf("rules = %d, want %d", len(routing.Rule), tt.wantRules)
+			}
+			if routing.DomainStrategy != router.DomainStrategy_IpIfNonMatch {
+				t.Errorf("DomainStrategy = %s, want %s", routing.DomainStrategy, router.DomainStrategy_IpIfNonMatch)
+			}
+
+			// The API rule comes first, so the API inbound is not blocked by the loopback rules.
+			rules := routing.Rule
+			if tt.wantAPI {
+				if len(rules[0].InboundTag) != 1 || rules[0].InboundTag[0] != v2ray.APITag || rules[0].GetTag() != v2ray.APITag {
+					t.Fatalf("first rule = %v, want the api rule", rules[0])
+				}
+
+				rules = rules[1:]
+			}
+
+			// The loopback rules follow, ahead of the configured rules.
+			for i := range loopback {
+				if rules[i].GetTag() != v2ray.BlockTag || len(rules[i].InboundTag) != 0 {
+					t.Errorf("rule %d = %v, want a loopback rule", i, rules[i])
+				}
+			}
+			if len(rules[0].Domain) != len(types.LoopbackDomains) || len(rules[1].Geoip[0].Cidr) != len(types.LoopbackCIDRs) {
+				t.Errorf("rules = %v, want the loopback rules", rules[:len(loopback)])
+			}
+		})
+	}
+}
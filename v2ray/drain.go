@@ -0,0 +1,50 @@
+package v2ray
+
+import (
+	"context"
+	"time"
+)
+
+// DrainCheckInterval represents the interval at which the remaining peers are checked while draining.
+const DrainCheckInterval = time.Second
+
+// isDraining checks whether the V2Ray server is draining.
+func (s *Server) isDraining() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.draining
+}
+
+// Drain puts the V2Ray server in the draining state and stops it once no peers remain.
+// While draining, AddPeer rejects new peers with sentinelsdk.ErrDraining, while the existing peers
+// remain served until they are removed with RemovePeer. If the context is done before the last peer
+// is removed, the server is stopped anyway and the error of the context is returned. The V2Ray core is
+// stopped gracefully, as with Stop, rather than killed.
+// Starting the server again leaves the draining state.
+func (s *Server) Drain(ctx context.Context) error {
+	// Reject new peers from now on.
+	s.mu.Lock()
+	s.draining = true
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(DrainCheckInterval)
+	defer ticker.Stop()
+
+	// Wait for the remaining peers to be removed, or for the context to be done.
+	var err error
+	for s.PeerCount() > 0 && err == nil {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	// Stop the server, without letting a done context interrupt the stop.
+	if stopErr := s.StopContext(context.WithoutCancel(ctx)); stopErr != nil {
+		return stopErr
+	}
+
+	return err
+}
@@ -0,0 +1,162 @@
+package v2ray
+
+import (
+	"context"
+	"errors"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	proxymancommand "github.com/v2fly/v2ray-core/v5/app/proxyman/command"
+	statscommand "github.com/v2fly/v2ray-core/v5/app/stats/command"
+	"github.com/v2fly/v2ray-core/v5/common/serial"
+	"github.com/v2fly/v2ray-core/v5/features/inbound"
+	"github.com/v2fly/v2ray-core/v5/features/outbound"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
+	"google.golang.org/grpc"
+)
+
+var (
+	_ proxymancommand.HandlerServiceClient = (*embeddedHandlerServiceClient)(nil)
+	_ statscommand.StatsServiceClient      = (*embeddedStatsServiceClient)(nil)
+)
+
+// embeddedHandlerServiceClient implements the handler service client on top of the inbound and outbound
+// managers of an in-process V2Ray instance, without going through gRPC.
+type embeddedHandlerServiceClient struct {
+	instance *core.Instance   // instance is the in-process V2Ray instance.
+	inbound  inbound.Manager  // inbound is the inbound manager of the instance.
+	outbound outbound.Manager // outbound is the outbound manager of the instance.
+}
+
+// newEmbeddedHandlerServiceClient creates a new handler service client for the given V2Ray instance.
+func newEmbeddedHandlerServiceClient(instance *core.Instance) (*embeddedHandlerServiceClient, error) {
+	client := &embeddedHandlerServiceClient{
+		instance: instance,
+	}
+
+	// Resolve the inbound and outbound managers of the instance.
+	if err := instance.RequireFeatures(func(im inbound.Manager, om outbound.Manager) {
+		client.inbound = im
+		client.outbound = om
+	}); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// AddInbound adds an inbound handler to the instance.
+func (c *embeddedHandlerServiceClient) AddInbound(_ context.Context, req *proxymancommand.AddInboundRequest, _ ...grpc.CallOption) (*proxymancommand.AddInboundResponse, error) {
+	if err := core.AddInboundHandler(c.instance, req.Inbound); err != nil {
+		return nil, err
+	}
+
+	return &proxymancommand.AddInboundResponse{}, nil
+}
+
+// RemoveInbound removes an inbound handler from the instance.
+func (c *embeddedHandlerServiceClient) RemoveInbound(ctx context.Context, req *proxymancommand.RemoveInboundRequest, _ ...grpc.CallOption) (*proxymancommand.RemoveInboundResponse, error) {
+	if err := c.inbound.RemoveHandler(ctx, req.Tag); err != nil {
+		return nil, err
+	}
+
+	return &proxymancommand.RemoveInboundResponse{}, nil
+}
+
+// AlterInbound applies an operation, such as adding or removing a user, to an inbound handler of the instance.
+func (c *embeddedHandlerServiceClient) AlterInbound(ctx context.Context, req *proxymancommand.AlterInboundRequest, _ ...grpc.CallOption) (*proxymancommand.AlterInboundResponse, error) {
+	// Decode the operation from the request.
+	raw, err := serial.GetInstanceOf(req.Operation)
+	if err != nil {
+		return nil, err
+	}
+
+	operation, ok := raw.(proxymancommand.InboundOperation)
+	if !ok {
+		return nil, errors.New("not an inbound operation")
+	}
+
+	// Apply the operation to the inbound handler with the requested tag.
+	handler, err := c.inbound.GetHandler(ctx, req.Tag)
+	if err != nil {
+		return nil, err
+	}
+	if err := operation.ApplyInbound(ctx, handler); err != nil {
+		return nil, err
+	}
+
+	return &proxymancommand.AlterInboundResponse{}, nil
+}
+
+// AddOutbound adds an outbound handler to the instance.
+func (c *embeddedHandlerServiceClient) AddOutbound(_ context.Context, req *proxymancommand.AddOutboundRequest, _ ...grpc.CallOption) (*proxymancommand.AddOutboundResponse, error) {
+	if err := core.AddOutboundHandler(c.instance, req.Outbound); err != nil {
+		return nil, err
+	}
+
+	return &proxymancommand.AddOutboundResponse{}, nil
+}
+
+// RemoveOutbound removes an outbound handler from the instance.
+func (c *embeddedHandlerServiceClient) RemoveOutbound(ctx context.Context, req *proxymancommand.RemoveOutboundRequest, _ ...grpc.CallOption) (*proxymancommand.RemoveOutboundResponse, error) {
+	if err := c.outbound.RemoveHandler(ctx, req.Tag); err != nil {
+		return nil, err
+	}
+
+	return &proxymancommand.RemoveOutboundResponse{}, nil
+}
+
+// AlterOutbound applies an operation to an outbound handler of the instance.
+func (c *embeddedHandlerServiceClient) AlterOutbound(ctx context.Context, req *proxymancommand.AlterOutboundRequest, _ ...grpc.CallOption) (*proxymancommand.AlterOutboundResponse, error) {
+	// Decode the operation from the request.
+	raw, err := serial.GetInstanceOf(req.Operation)
+	if err != nil {
+		return nil, err
+	}
+
+	operation, ok := raw.(proxymancommand.OutboundOperation)
+	if !ok {
+		return nil, errors.New("not an outbound operation")
+	}
+
+	// Apply the operation to the outbound handler with the requested tag.
+	if err := operation.ApplyOutbound(ctx, c.outbound.GetHandler(req.Tag)); err != nil {
+		return nil, err
+	}
+
+	return &proxymancommand.AlterOutboundResponse{}, nil
+}
+
+// embeddedStatsServiceClient implements the stats service client on top of the stats manager
+// of an in-process V2Ray instance, without going through gRPC.
+type embeddedStatsServiceClient struct {
+	server statscommand.StatsServiceServer // server is the stats service backed by the stats manager.
+}
+
+// newEmbeddedStatsServiceClient creates a new stats service client for the given V2Ray instance.
+func newEmbeddedStatsServiceClient(instance *core.Instance) (*embeddedStatsServiceClient, error) {
+	client := &embeddedStatsServiceClient{}
+
+	// Resolve the stats manager of the instance.
+	if err := instance.RequireFeatures(func(m stats.Manager) {
+		client.server = statscommand.NewStatsServer(m)
+	}); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// GetStats returns the value of a single counter.
+func (c *embeddedStatsServiceClient) GetStats(ctx context.Context, req *statscommand.GetStatsRequest, _ ...grpc.CallOption) (*statscommand.GetStatsResponse, error) {
+	return c.server.GetStats(ctx, req)
+}
+
+// QueryStats returns the values of the counters matching the request patterns.
+func (c *embeddedStatsServiceClient) QueryStats(ctx context.Context, req *statscommand.QueryStatsRequest, _ ...grpc.CallOption) (*statscommand.QueryStatsResponse, error) {
+	return c.server.QueryStats(ctx, req)
+}
+
+// GetSysStats returns the runtime statistics of the process.
+func (c *embeddedStatsServiceClient) GetSysStats(ctx context.Context, req *statscommand.SysStatsRequest, _ ...grpc.CallOption) (*statscommand.SysStatsResponse, error) {
+	return c.server.GetSysStats(ctx, req)
+}
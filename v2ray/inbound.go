@@ -0,0 +1,295 @@
+package v2ray
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	proxymancommand "github.com/v2fly/v2ray-core/v5/app/proxyman/command"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/common/serial"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+// addInbound adds an inbound to the V2Ray server using the handler service, and records its handler
+// configuration as applied. The caller must hold mu.
+func (s *Server) addInbound(ctx context.Context, client proxymancommand.HandlerServiceClient, config *core.InboundHandlerConfig) error {
+	// Prepare gRPC request to add the inbound to the handler.
+	req := &proxymancommand.AddInboundRequest{
+		Inbound: config,
+	}
+
+	// Send the request to add the inbound to the handler.
+	if _, err := client.AddInbound(ctx, req); err != nil {
+		return err
+	}
+
+	s.applied[config.Tag] = config
+	return nil
+}
+
+// removeInbound removes an inbound from the V2Ray server using the handler service, and forgets it along
+// with the users added to it. It does not return an error if the inbound does not exist.
+// The caller must hold mu.
+func (s *Server) removeInbound(ctx context.Context, client proxymancommand.HandlerServiceClient, tag string) error {
+	// Prepare gRPC request to remove the inbound from the handler.
+	req := &proxymancommand.RemoveInboundRequest{
+		Tag: tag,
+	}
+
+	// Send the request to remove the inbound from the handler.
+	_, err := client.RemoveInbound(ctx, req)
+	if err != nil {
+		// If the inbound is not found, continue without error.
+		// The inbound manager reports a missing inbound with the generic "no clue" error.
+		if !strings.Contains(err.Error(), common.ErrNoClue.Error()) {
+			return err
+		}
+	}
+
+	delete(s.applied, tag)
+	delete(s.users, tag)
+
+	return nil
+}
+
+// addUser adds a user to an inbound of the V2Ray server using the handler service, and records it
+// as a user of the inbound. The caller must hold mu.
+func (s *Server) addUser(ctx context.Context, client proxymancommand.HandlerServiceClient, tag string, user *protocol.User) error {
+	// Prepare gRPC request to add a user to the handler.
+	req := &proxymancommand.AlterInboundRequest{
+		Tag: tag,
+		Operation: serial.ToTypedMessage(
+			&proxymancommand.AddUserOperation{
+				User: user,
+			},
+		),
+	}
+
+	// Send the request to add a user to the handler.
+	if _, err := client.AlterInbound(ctx, req); err != nil {
+		return err
+	}
+
+	if s.users[tag] == nil {
+		s.users[tag] = make(map[string]bool)
+	}
+
+	s.users[tag][user.Email] = true
+	return nil
+}
+
+// removeUser removes a user from an inbound of the V2Ray server using the handler service, and forgets it.
+// It does not return an error if the user does not exist. The caller must hold mu.
+func (s *Server) removeUser(ctx context.Context, client proxymancommand.HandlerServiceClient, tag, email string) error {
+	// Prepare gRPC request to remove a user from the handler.
+	req := &proxymancommand.AlterInboundRequest{
+		Tag: tag,
+		Operation: serial.ToTypedMessage(
+			&proxymancommand.RemoveUserOperation{
+				Email: email,
+			},
+		),
+	}
+
+	// Send the request to remove a user from the handler.
+	_, err := client.AlterInbound(ctx, req)
+	if err != nil {
+		// If the user is not found, continue without error.
+		if !strings.Contains(err.Error(), "not found") {
+			return err
+		}
+	}
+
+	delete(s.users[tag], email)
+	return nil
+}
+
+// Inbounds returns a copy of the list of inbounds currently served to peers.
+func (s *Server) Inbounds() []*types.InboundConfig {
+	// Check if the configuration is nil.
+	if s.config == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*types.InboundConfig(nil), s.config.Inbounds...)
+}
+
+// persist rebuilds the V2Ray core configuration from the server configuration, so a restart
+// of the V2Ray core serves the inbounds added or removed at runtime.
+// In exec mode, the configuration is written to the configuration file.
+func (s *Server) persist() error {
+	// Build the V2Ray core configuration from the server configuration.
+	config, err := s.config.Build()
+	if err != nil {
+		return err
+	}
+
+	// Prepare the process to run the configuration on the next start.
+	return s.process.init(config)
+}
+
+// AddInbound adds a new inbound to the running V2Ray server and to the server configuration.
+// The peers of the proxy which did not choose their inbounds are added to the new inbound, so they
+// can connect through it right away. Inbounds dedicated to peers are created on free ports within
+// the range of the new inbound.
+func (s *Server) AddInbound(ctx context.Context, inbound *types.InboundConfig) error {
+	// Check if the configuration is nil.
+	if s.config == nil {
+		return errors.New("nil config")
+	}
+
+	// Serialize the change with the peer operations and the other inbound operations.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Ensure the configuration remains valid with the new inbound.
+	candidate := *s.config
+	candidate.Inbounds = append(append([]*types.InboundConfig(nil), s.config.Inbounds...), inbound)
+	if err := candidate.Validate(); err != nil {
+		return err
+	}
+
+	// Establish a gRPC client connection to the handler service.
+	conn, client, err := s.handlerServiceClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Ensure the connection is closed when done.
+	defer func() {
+		if err = conn.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	// Create the inbound of a multi-user proxy right away.
+	if inbound.Proxy.IsMultiUser() {
+		config, err := inbound.HandlerConfig()
+		if err != nil {
+			return err
+		}
+
+		// Add the inbound to the running V2Ray server.
+		if err := s.addInbound(ctx, client, config); err != nil {
+			return err
+		}
+	}
+
+	// Collect the peers served by every inbound of the proxy, which did not choose their inbounds.
+	var peers []*types.Peer
+	if err := s.peers.Iterate(func(_ string, value *types.Peer) (bool, error) {
+		if value.Proxy == inbound.Proxy && !value.Pinned {
+			peers = append(peers, value)
+		}
+
+		return false, nil
+	}); err != nil {
+		return err
+	}
+
+	// Add the peers to the new inbound. A failure leaves the inbound in place, so the peers
+	// added so far remain served.
+	for _, peer := range peers {
+		if err := s.addPeerToInbound(ctx, client, inbound, peer); err != nil {
+			s.config.Inbounds = candidate.Inbounds
+			return errors.Join(err, s.persist())
+		}
+	}
+
+	// Keep the server configuration in sync with the running V2Ray server.
+	s.config.Inbounds = candidate.Inbounds
+	return s.persist()
+}
+
+// RemoveInbound removes the inbound with the given tag from the running V2Ray server and from
+// the server configuration. The peers served by the inbound are disconnected from it, and the peers
+// left without any inbound are removed.
+func (s *Server) RemoveInbound(ctx context.Context, tag string) error {
+	// Check if the configuration is nil.
+	if s.config == nil {
+		return errors.New("nil config")
+	}
+
+	// Serialize the change with the peer operations and the other inbound operations.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Look up the inbound with the given tag.
+	index := -1
+	for i, item := range s.config.Inbounds {
+		if item.Tag() == tag {
+			index = i
+		}
+	}
+	if index == -1 {
+		return fmt.Errorf("inbound %s: %w", tag, sentinelsdk.ErrNotFound)
+	}
+
+	// Ensure at least one inbound remains served.
+	if len(s.config.Inbounds) == 1 {
+		return errors.New("cannot remove the last inbound")
+	}
+
+	inbound := s.config.Inbounds[index]
+
+	// Establish a gRPC client connection to the handler service.
+	conn, client, err := s.handlerServiceClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Ensure the connection is closed when done.
+	defer func() {
+		if err = conn.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	// Collect the peers served by the inbound.
+	var peers []*types.Peer
+	if err := s.peers.Iterate(func(_ string, value *types.Peer) (bool, error) {
+		if value.HasInbound(inbound.Tag()) {
+			peers = append(peers, value)
+		}
+
+		return false, nil
+	}); err != nil {
+		return err
+	}
+
+	// Remove the inbound, along with the inbounds dedicated to its peers.
+	if inbound.Proxy.IsMultiUser() {
+		if err := s.removeInbound(ctx, client, inbound.Tag()); err != nil {
+			return err
+		}
+	} else {
+		for _, peer := range peers {
+			if err := s.removeInbound(ctx, client, inbound.PeerTag(peer.Email)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Remove the peers left without any inbound from the local collection.
+	for _, peer := range peers {
+		peer.RemoveInbound(inbound.Tag())
+		if len(peer.Inbounds) == 0 {
+			s.peers.Delete(peer.Key())
+		}
+	}
+
+	// Keep the server configuration in sync with the running V2Ray server.
+	inbounds := append([]*types.InboundConfig(nil), s.config.Inbounds[:index]...)
+	s.config.Inbounds = append(inbounds, s.config.Inbounds[index+1:]...)
+
+	return s.persist()
+}
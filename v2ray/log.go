@@ -0,0 +1,225 @@
+package v2ray
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	commonlog "github.com/v2fly/v2ray-core/v5/common/log"
+	"github.com/v2fly/v2ray-core/v5/common/serial"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+// logTimeLayout represents the layout of the timestamp V2Ray prefixes its console log lines with.
+const logTimeLayout = "2006/01/02 15:04:05"
+
+// logSink receives the output of the V2Ray core, writes it to a structured logger and delivers
+// the parsed access records to the subscribers.
+type logSink struct {
+	mu          sync.RWMutex                      // mu protects the logger and the subscribers.
+	logger      *slog.Logger                      // logger is the structured logger the output is written to.
+	next        int                               // next is the identifier of the next subscriber.
+	subscribers map[int]func(*types.AccessRecord) // subscribers are the functions receiving the access records.
+}
+
+// newLogSink creates a new log sink writing to the default structured logger.
+func newLogSink() *logSink {
+	return &logSink{
+		logger:      slog.Default().With("module", "v2ray"),
+		subscribers: make(map[int]func(*types.AccessRecord)),
+	}
+}
+
+// setLogger replaces the structured logger the output is written to.
+func (s *logSink) setLogger(logger *slog.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logger = logger
+}
+
+// subscribe registers a function receiving the access records, and returns a function unregistering it.
+func (s *logSink) subscribe(fn func(*types.AccessRecord)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.next
+	s.next++
+	s.subscribers[id] = fn
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.subscribers, id)
+	}
+}
+
+// snapshot returns the current logger and subscribers, so that they can be called without holding the lock.
+func (s *logSink) snapshot() (*slog.Logger, []func(*types.AccessRecord)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subscribers := make([]func(*types.AccessRecord), 0, len(s.subscribers))
+	for _, fn := range s.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+
+	return s.logger, subscribers
+}
+
+// handleAccess writes an access record to the logger and delivers it to the subscribers.
+func (s *logSink) handleAccess(record *types.AccessRecord) {
+	logger, subscribers := s.snapshot()
+	logger.LogAttrs(
+		context.Background(),
+		slog.LevelInfo,
+		"access",
+		slog.String("source", record.Source),
+		slog.String("status", record.Status.String()),
+		slog.String("destination", record.Destination),
+		slog.String("detour", record.Detour),
+		slog.String("reason", record.Reason),
+		slog.String("email", record.Email),
+	)
+
+	for _, fn := range subscribers {
+		fn(record)
+	}
+}
+
+// handleGeneral writes a general message of the given severity to the logger.
+func (s *logSink) handleGeneral(severity commonlog.Severity, msg string) {
+	s.mu.RLock()
+	logger := s.logger
+	s.mu.RUnlock()
+
+	logger.Log(context.Background(), slogLevel(severity), msg)
+}
+
+// handleMessage handles a log message of an in-process V2Ray instance (embedded mode only).
+// Debug messages are dropped, matching the severity of the logs written by the V2Ray binary.
+func (s *logSink) handleMessage(msg commonlog.Message) {
+	switch msg := msg.(type) {
+	case *commonlog.AccessMessage:
+		record, err := types.ParseAccessRecord(msg.String())
+		if err != nil {
+			return
+		}
+
+		record.Time = time.Now()
+		s.handleAccess(record)
+	case *commonlog.GeneralMessage:
+		if msg.Severity > commonlog.Severity_Info {
+			return
+		}
+
+		s.handleGeneral(msg.Severity, serial.ToString(msg.Content))
+	}
+}
+
+// handleLine handles a line of the console output of the V2Ray binary (exec mode only).
+func (s *logSink) handleLine(line string) {
+	if line == "" {
+		return
+	}
+
+	// Strip the timestamp prefix, falling back to the current time for lines without one.
+	t := time.Now()
+	if len(line) > len(logTimeLayout) && line[len(logTimeLayout)] == ' ' {
+		if v, err := time.ParseInLocation(logTimeLayout, line[:len(logTimeLayout)], time.Local); err == nil {
+			t = v
+			line = line[len(logTimeLayout)+1:]
+		}
+	}
+
+	// General messages are prefixed with their severity, as in "[Warning] message".
+	if strings.HasPrefix(line, "[") {
+		if i := strings.Index(line, "] "); i >= 0 {
+			if severity, ok := commonlog.Severity_value[line[1:i]]; ok {
+				s.handleGeneral(commonlog.Severity(severity), line[i+2:])
+				return
+			}
+		}
+	}
+
+	// Parse the line as an access message, and log any other line as is.
+	record, err := types.ParseAccessRecord(line)
+	if err != nil {
+		s.handleGeneral(commonlog.Severity_Info, line)
+		return
+	}
+
+	record.Time = t
+	s.handleAccess(record)
+}
+
+// writer returns an io.Writer passing each line written to it to handleLine.
+func (s *logSink) writer() *lineWriter {
+	return &lineWriter{fn: s.handleLine}
+}
+
+// slogLevel converts a V2Ray log severity to the corresponding structured log level.
+func slogLevel(severity commonlog.Severity) slog.Level {
+	switch severity {
+	case commonlog.Severity_Error:
+		return slog.LevelError
+	case commonlog.Severity_Warning:
+		return slog.LevelWarn
+	case commonlog.Severity_Debug:
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// lineWriter is an io.Writer splitting the written data into lines, which are passed to a function.
+// Incomplete lines are buffered until the rest of the line is written.
+type lineWriter struct {
+	buf bytes.Buffer      // buf holds the incomplete line written so far.
+	fn  func(line string) // fn is the function receiving each complete line.
+}
+
+// Write writes the data to the buffer, passing each complete line to the function.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+
+	for {
+		// Find the end of the next complete line.
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+
+		line := string(w.buf.Next(i + 1))
+		w.fn(strings.TrimRight(line, "\r\n"))
+	}
+
+	return len(p), nil
+}
+
+// SetLogger sets the structured logger the output of the V2Ray server is written to.
+// Access records are logged at the Info level with the message "access", and the other messages
+// of the V2Ray core at the level matching their severity. It defaults to slog.Default.
+func (s *Server) SetLogger(logger *slog.Logger) {
+	s.process.logs.setLogger(logger)
+}
+
+// SubscribeAccess registers a function receiving an AccessRecord for every connection accepted or rejected
+// by the V2Ray server, and returns a function unregistering it.
+// The function is called synchronously from the goroutine handling the connection, so it must not block.
+// In embedded mode, V2Ray routes log messages through a process-wide handler, so only one in-process
+// V2Ray core should run at a time for the records to be attributed to the right server.
+func (s *Server) SubscribeAccess(fn func(*types.AccessRecord)) func() {
+	return s.process.logs.subscribe(fn)
+}
+
+// SetLogger sets the structured logger the output of the V2Ray client is written to.
+// It defaults to slog.Default.
+func (c *Client) SetLogger(logger *slog.Logger) {
+	c.process.logs.setLogger(logger)
+}
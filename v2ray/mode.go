@@ -0,0 +1,42 @@
+package v2ray
+
+// Mode represents different ways of running the V2Ray core.
+type Mode byte
+
+const (
+	// ModeUnspecified represents an unspecified mode, which defaults to ModeExec.
+	ModeUnspecified Mode = 0x00 + iota
+	// ModeExec represents running the V2Ray binary as a child process, managed through the V2Ray API.
+	ModeExec
+	// ModeEmbedded represents running the V2Ray core in-process, managed through its feature managers.
+	ModeEmbedded
+)
+
+// String returns a human-readable string representation of the Mode type.
+func (m Mode) String() string {
+	switch m {
+	case ModeExec:
+		return "exec"
+	case ModeEmbedded:
+		return "embedded"
+	default:
+		return ""
+	}
+}
+
+// IsEmbedded checks whether the V2Ray core runs in-process.
+func (m Mode) IsEmbedded() bool {
+	return m == ModeEmbedded
+}
+
+// ModeFromString converts a string representation to the corresponding Mode type.
+func ModeFromString(s string) Mode {
+	switch s {
+	case "exec":
+		return ModeExec
+	case "embedded":
+		return ModeEmbedded
+	default:
+		return ModeUnspecified
+	}
+}
@@ -0,0 +1,516 @@
+package v2ray
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	applog "github.com/v2fly/v2ray-core/v5/app/log"
+	proxymancommand "github.com/v2fly/v2ray-core/v5/app/proxyman/command"
+	statscommand "github.com/v2fly/v2ray-core/v5/app/stats/command"
+	commonlog "github.com/v2fly/v2ray-core/v5/common/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+
+	// Register all the V2Ray features, proxies and transports used by the generated configurations.
+	_ "github.com/v2fly/v2ray-core/v5/main/distro/all"
+)
+
+// nopCloser is an io.Closer which does nothing, returned in place of a gRPC connection in embedded mode.
+type nopCloser struct{}
+
+// Close does nothing.
+func (nopCloser) Close() error {
+	return nil
+}
+
+const (
+	// ReadyCheckInterval represents the interval at which the V2Ray API is probed while waiting for it to be ready.
+	ReadyCheckInterval = 100 * time.Millisecond
+
+	// StopTimeout represents the time allowed for the V2Ray binary to exit once asked to terminate,
+	// before it is killed.
+	StopTimeout = 10 * time.Second
+)
+
+// process runs a V2Ray core configuration, either in-process or by executing the V2Ray binary.
+type process struct {
+	apiPort    uint16   // apiPort is the local port of the V2Ray API (exec mode only).
+	configFile string   // configFile is the path of the configuration file (exec mode only).
+	logs       *logSink // logs receives the log output of the V2Ray core.
+	mode       Mode     // mode is the way the V2Ray core is run.
+
+	mu        sync.Mutex              // mu protects the fields below, which change as the V2Ray core starts and exits.
+	cmd       *exec.Cmd               // cmd is the command for running the V2Ray binary (exec mode only).
+	config    *core.Config            // config is the V2Ray core configuration to run.
+	crashes   int                     // crashes counts the exits of the V2Ray binary not caused by stop (exec mode only).
+	done      chan struct{}           // done is closed when the V2Ray binary exits (exec mode only).
+	follower  func(commonlog.Message) // follower receives the log messages of the in-process instance (embedded mode only).
+	instance  *core.Instance          // instance is the in-process V2Ray instance (embedded mode only).
+	lastErr   error                   // lastErr is the last error of the V2Ray core, if any.
+	restarts  int                     // restarts counts the starts of the V2Ray core following a previous start.
+	startedAt time.Time               // startedAt is the time the V2Ray core was last started.
+	stopping  bool                    // stopping is set when the V2Ray binary is killed by stop (exec mode only).
+}
+
+// newProcess creates a new process running in the given mode.
+func newProcess(mode Mode, configFile string, apiPort uint16) *process {
+	return &process{
+		apiPort:    apiPort,
+		configFile: configFile,
+		logs:       newLogSink(),
+		mode:       mode,
+	}
+}
+
+// init prepares the process to run the given configuration.
+// In exec mode, the configuration is written to the configuration file in the protobuf format.
+func (p *process) init(config *core.Config) error {
+	p.mu.Lock()
+	p.config = config
+	p.mu.Unlock()
+
+	if p.mode.IsEmbedded() {
+		return nil
+	}
+
+	// Encode the core configuration in the protobuf format.
+	buf, err := proto.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	// Write the encoded configuration to the configuration file.
+	return os.WriteFile(p.configFile, buf, 0600)
+}
+
+// coreConfig returns the V2Ray core configuration the process runs.
+func (p *process) coreConfig() *core.Config {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.config
+}
+
+// setLastErr records the given error as the last error of the V2Ray core, and returns it.
+// The caller must hold mu.
+func (p *process) setLastErr(err error) error {
+	if err != nil {
+		p.lastErr = err
+	}
+
+	return err
+}
+
+// getLastErr returns the last error of the V2Ray core, if any.
+func (p *process) getLastErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.lastErr
+}
+
+// start starts the V2Ray core.
+// It returns sentinelsdk.ErrServiceRunning if the V2Ray core is already running.
+func (p *process) start(ctx context.Context) error {
+	// Check if the context is done.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Check if the V2Ray core is already running, rather than orphaning it.
+	if p.running() {
+		return sentinelsdk.ErrServiceRunning
+	}
+
+	if p.mode.IsEmbedded() {
+		// Check if the configuration is nil.
+		if p.config == nil {
+			return errors.New("nil config")
+		}
+
+		// Create a new in-process V2Ray instance from the configuration.
+		instance, err := core.New(p.config)
+		if err != nil {
+			return p.setLastErr(err)
+		}
+
+		// Follow the log messages of the instance, which are not written anywhere else.
+		if logger, ok := instance.GetFeature((*applog.Instance)(nil)).(*applog.Instance); ok {
+			p.follower = p.logs.handleMessage
+			logger.AddFollower(p.follower)
+		}
+
+		// Start the instance.
+		if err := instance.Start(); err != nil {
+			p.removeFollower(instance)
+			return p.setLastErr(err)
+		}
+
+		p.instance = instance
+		p.started()
+
+		return nil
+	}
+
+	// Create a new command to execute the V2Ray binary.
+	cmd := exec.Command(
+		execFile(), // Get the path to the V2Ray binary.
+		strings.Split(
+			fmt.Sprintf("run --config %s --format protobuf", p.configFile), // Construct the command-line arguments.
+			" ",
+		)...,
+	)
+
+	// Capture the standard output and error streams line by line, parsing the access logs.
+	cmd.Stdout = p.logs.writer()
+	cmd.Stderr = p.logs.writer()
+
+	// Start the V2Ray binary by executing the command.
+	p.stopping = false
+	if err := cmd.Start(); err != nil {
+		return p.setLastErr(err)
+	}
+
+	// Wait for the V2Ray binary to exit in the background, recording why it exited.
+	done := make(chan struct{})
+	go func() {
+		err := cmd.Wait()
+
+		// Record the exit as a crash, along with its error, unless the V2Ray binary was stopped by stop.
+		p.mu.Lock()
+		if !p.stopping {
+			p.crashes++
+			if err != nil {
+				p.lastErr = fmt.Errorf("v2ray exited: %w", err)
+			} else {
+				p.lastErr = errors.New("v2ray exited")
+			}
+		}
+		p.mu.Unlock()
+
+		close(done)
+	}()
+
+	p.cmd = cmd
+	p.done = done
+	p.started()
+
+	return nil
+}
+
+// started records that the V2Ray core started, counting a restart if it was started before.
+// The caller must hold mu.
+func (p *process) started() {
+	if !p.startedAt.IsZero() {
+		p.restarts++
+	}
+
+	p.startedAt = time.Now()
+}
+
+// counters returns the number of restarts and crashes of the V2Ray core.
+func (p *process) counters() (restarts, crashes int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.restarts, p.crashes
+}
+
+// stop stops the V2Ray core. In embedded mode, the instance is closed, which closes its listeners and
+// connections. In exec mode, the V2Ray binary is asked to terminate with SIGTERM, and killed only if it
+// does not exit within StopTimeout or before the context is done.
+func (p *process) stop(ctx context.Context) error {
+	p.mu.Lock()
+
+	if p.mode.IsEmbedded() {
+		defer p.mu.Unlock()
+
+		// Check if the instance is nil.
+		if p.instance == nil {
+			return sentinelsdk.ErrServiceNotRunning
+		}
+
+		// Stop following the log messages, and close the instance, stopping all its features.
+		p.removeFollower(p.instance)
+		err := p.instance.Close()
+		p.instance = nil
+
+		return err
+	}
+
+	// Check if the command is nil.
+	cmd, done := p.cmd, p.done
+	if cmd == nil {
+		// If the command is nil, the V2Ray binary was never started.
+		p.mu.Unlock()
+		return sentinelsdk.ErrServiceNotRunning
+	}
+
+	p.stopping = true
+	p.mu.Unlock()
+
+	// Ask the V2Ray binary to terminate, and wait for it to exit without holding the lock needed
+	// by the waiting goroutine. Platforms without SIGTERM kill it right away.
+	graceCtx, cancel := context.WithTimeout(ctx, StopTimeout)
+	defer cancel()
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		cancel()
+	}
+
+	select {
+	case <-done:
+	case <-graceCtx.Done():
+		// Kill the V2Ray binary, which did not exit in time, and wait for it to exit.
+		if err := cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			return err
+		}
+
+		<-done
+	}
+
+	// Forget the command, unless the V2Ray binary was started again meanwhile.
+	p.mu.Lock()
+	if p.cmd == cmd {
+		p.cmd = nil
+	}
+	p.mu.Unlock()
+
+	return nil
+}
+
+// removeFollower stops following the log messages of the given in-process instance (embedded mode only).
+// The caller must hold mu.
+func (p *process) removeFollower(instance *core.Instance) {
+	if p.follower == nil {
+		return
+	}
+
+	if logger, ok := instance.GetFeature((*applog.Instance)(nil)).(*applog.Instance); ok {
+		logger.RemoveFollower(p.follower)
+	}
+
+	p.follower = nil
+}
+
+// running checks whether the V2Ray core is running. The caller must hold mu.
+func (p *process) running() bool {
+	if p.mode.IsEmbedded() {
+		return p.instance != nil
+	}
+
+	if p.cmd == nil {
+		return false
+	}
+
+	select {
+	case <-p.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// isRunning checks whether the V2Ray core is running.
+func (p *process) isRunning() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.running()
+}
+
+// pid returns the process ID of the V2Ray core, or zero if it is not running.
+// In embedded mode, it is the process ID of the current process.
+func (p *process) pid() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.running() {
+		return 0
+	}
+	if p.mode.IsEmbedded() {
+		return os.Getpid()
+	}
+
+	return p.cmd.Process.Pid
+}
+
+// uptime returns the time elapsed since the V2Ray core started, or zero if it is not running.
+func (p *process) uptime() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.running() {
+		return 0
+	}
+
+	return time.Since(p.startedAt)
+}
+
+// embeddedInstance returns the in-process V2Ray instance, or sentinelsdk.ErrServiceNotRunning if it is not running.
+func (p *process) embeddedInstance() (*core.Instance, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.instance == nil {
+		return nil, sentinelsdk.ErrServiceNotRunning
+	}
+
+	return p.instance, nil
+}
+
+// isReady checks whether the V2Ray API responds to requests.
+func (p *process) isReady(ctx context.Context) bool {
+	// Establish a gRPC client connection to the stats service.
+	conn, client, err := p.statsServiceClient(ctx)
+	if err != nil {
+		return false
+	}
+
+	// Ensure the connection is closed when done.
+	defer func() {
+		if err = conn.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	// Send a request for the system statistics, which are always available.
+	_, err = client.GetSysStats(ctx, &statscommand.SysStatsRequest{})
+	return err == nil
+}
+
+// waitReady blocks until the V2Ray API responds to requests, the V2Ray core stops, or the context is done.
+func (p *process) waitReady(ctx context.Context) error {
+	ticker := time.NewTicker(ReadyCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		// Check if the V2Ray core stopped, returning the reason if known.
+		if !p.isRunning() {
+			if err := p.getLastErr(); err != nil {
+				return err
+			}
+
+			return sentinelsdk.ErrServiceNotRunning
+		}
+
+		// Probe the V2Ray API, giving up on the attempt at the next tick.
+		probeCtx, cancel := context.WithTimeout(ctx, ReadyCheckInterval)
+		ready := p.isReady(probeCtx)
+		cancel()
+
+		if ready {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// clientConn establishes a gRPC client connection to the V2Ray API.
+// It returns the error of the context if the context is done before the connection is established.
+func (p *process) clientConn(ctx context.Context) (*grpc.ClientConn, error) {
+	// Define the target address for the gRPC client connection.
+	target := fmt.Sprintf("127.0.0.1:%d", p.apiPort)
+
+	// Establish a gRPC client connection with specified options:
+	// - WithBlock: Blocks until the underlying connection is established.
+	// - WithTransportCredentials: Configures insecure transport credentials for the connection.
+	return grpc.DialContext(
+		ctx,
+		target,
+		grpc.WithBlock(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+}
+
+// handlerServiceClient returns a client of the V2Ray handler service, along with a closer
+// releasing its resources. In embedded mode, the client calls the in-process instance directly.
+func (p *process) handlerServiceClient(ctx context.Context) (io.Closer, proxymancommand.HandlerServiceClient, error) {
+	if p.mode.IsEmbedded() {
+		// Check if the instance is running.
+		instance, err := p.embeddedInstance()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		client, err := newEmbeddedHandlerServiceClient(instance)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return nopCloser{}, client, nil
+	}
+
+	// Check if the V2Ray binary is running, rather than waiting for a connection that cannot succeed.
+	if !p.isRunning() {
+		return nil, nil, sentinelsdk.ErrServiceNotRunning
+	}
+
+	// Establish a gRPC client connection using the clientConn method.
+	conn, err := p.clientConn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create a new HandlerServiceClient using the established connection.
+	client := proxymancommand.NewHandlerServiceClient(conn)
+
+	// Return both the connection and the client.
+	return conn, client, nil
+}
+
+// statsServiceClient returns a client of the V2Ray stats service, along with a closer
+// releasing its resources. In embedded mode, the client calls the in-process instance directly.
+func (p *process) statsServiceClient(ctx context.Context) (io.Closer, statscommand.StatsServiceClient, error) {
+	if p.mode.IsEmbedded() {
+		// Check if the instance is running.
+		instance, err := p.embeddedInstance()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		client, err := newEmbeddedStatsServiceClient(instance)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return nopCloser{}, client, nil
+	}
+
+	// Check if the V2Ray binary is running, rather than waiting for a connection that cannot succeed.
+	if !p.isRunning() {
+		return nil, nil, sentinelsdk.ErrServiceNotRunning
+	}
+
+	// Establish a gRPC client connection using the clientConn method.
+	conn, err := p.clientConn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create a new StatsServiceClient using the established connection.
+	client := statscommand.NewStatsServiceClient(conn)
+
+	// Return both the connection and the client.
+	return conn, client, nil
+}
@@ -0,0 +1,125 @@
+package v2ray
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// fakeBinary installs a shell script in place of the V2Ray binary for the duration of the test.
+func fakeBinary(t *testing.T, script string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts cannot replace the V2Ray binary on windows")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, execFile()), []byte("#!/bin/sh\n"+script), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestProcess_Stop(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		timeout time.Duration
+	}{
+		{
+			name:    "exits on sigterm",
+			script:  "trap 'exit 0' TERM\nwhile true; do sleep 0.05; done\n",
+			timeout: StopTimeout,
+		},
+		{
+			name:    "killed after the deadline",
+			script:  "trap '' TERM\nwhile true; do sleep 0.05; done\n",
+			timeout: 300 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeBinary(t, tt.script)
+
+			p := newProcess(ModeExec, filepath.Join(t.TempDir(), ProtobufConfigFilename), APIPort)
+			if err := p.start(context.Background()); err != nil {
+				t.Fatalf("start() error = %v", err)
+			}
+
+			// Give the shell time to install its trap.
+			time.Sleep(200 * time.Millisecond)
+
+			// A second start must not orphan the running binary.
+			if err := p.start(context.Background()); !errors.Is(err, sentinelsdk.ErrServiceRunning) {
+				t.Fatalf("start() error = %v, want %v", err, sentinelsdk.ErrServiceRunning)
+			}
+			if !p.isRunning() || p.pid() == 0 {
+				t.Fatal("isRunning() = false, want true")
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			begin := time.Now()
+			if err := p.stop(ctx); err != nil {
+				t.Fatalf("stop() error = %v", err)
+			}
+			if elapsed := time.Since(begin); elapsed > tt.timeout+time.Second {
+				t.Fatalf("stop() took %s, want at most %s", elapsed, tt.timeout)
+			}
+
+			if p.isRunning() || p.pid() != 0 || p.uptime() != 0 {
+				t.Fatal("isRunning() = true after stop, want false")
+			}
+			if err := p.getLastErr(); err != nil {
+				t.Fatalf("getLastErr() = %v, want nil after stop", err)
+			}
+			if err := p.stop(context.Background()); !errors.Is(err, sentinelsdk.ErrServiceNotRunning) {
+				t.Fatalf("stop() error = %v, want %v", err, sentinelsdk.ErrServiceNotRunning)
+			}
+		})
+	}
+}
+
+func TestProcess_Exit(t *testing.T) {
+	fakeBinary(t, "exit 3\n")
+
+	p := newProcess(ModeExec, filepath.Join(t.TempDir(), ProtobufConfigFilename), APIPort)
+	if err := p.start(context.Background()); err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+
+	// waitReady returns the reason the binary exited.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := p.waitReady(ctx); err == nil || errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("waitReady() error = %v, want the exit error", err)
+	}
+	if p.isRunning() {
+		t.Fatal("isRunning() = true, want false")
+	}
+
+	// The binary can be started again once it exited.
+	fakeBinary(t, "trap 'exit 0' TERM\nwhile true; do sleep 0.05; done\n")
+	if err := p.start(context.Background()); err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+	if err := p.stop(context.Background()); err != nil {
+		t.Fatalf("stop() error = %v", err)
+	}
+
+	// The first exit is a crash, while the exit caused by stop is not.
+	if restarts, crashes := p.counters(); restarts != 1 || crashes != 1 {
+		t.Fatalf("counters() = %d restarts, %d crashes, want 1 and 1", restarts, crashes)
+	}
+}
@@ -0,0 +1,176 @@
+package v2ray
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	proxymancommand "github.com/v2fly/v2ray-core/v5/app/proxyman/command"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+// Reconcile re-asserts the inbounds of the server configuration and every peer of the local collection
+// against the inbounds and users the server added to the V2Ray core since it started, for example after
+// a request was lost. Missing inbounds, users and inbounds dedicated to peers are added back, without
+// disturbing the ones which exist. Users and inbounds dedicated to peers unknown to the local collection
+// are removed. Reconcile continues after a failure and returns all the errors encountered.
+func (s *Server) Reconcile(ctx context.Context) error {
+	// Check if the configuration is nil.
+	if s.config == nil {
+		return errors.New("nil config")
+	}
+
+	// Serialize the reconciliation with the peer operations and the inbound operations.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Establish a gRPC client connection to the handler service.
+	conn, client, err := s.handlerServiceClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Ensure the connection is closed when done.
+	defer func() {
+		if err = conn.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	var errs []error
+
+	// Add back the missing inbounds of multi-user proxies.
+	for _, inbound := range s.config.Inbounds {
+		if !inbound.Proxy.IsMultiUser() {
+			continue
+		}
+		if _, ok := s.applied[inbound.Tag()]; ok {
+			continue
+		}
+
+		config, err := inbound.HandlerConfig()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := s.removeInbound(ctx, client, inbound.Tag()); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := s.addInbound(ctx, client, config); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Add back the missing users and inbounds of each peer.
+	if err := s.peers.Iterate(func(_ string, value *types.Peer) (bool, error) {
+		if err := s.reconcilePeer(ctx, client, value); err != nil {
+			errs = append(errs, err)
+		}
+
+		return false, nil
+	}); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Remove the users and inbounds of the peers unknown to the local collection.
+	if err := s.removeUnknownPeers(ctx, client); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// reconcilePeer adds back the missing users and inbounds of the given peer. Inbounds which no longer
+// exist in the server configuration are dropped from the peer information. The caller must hold mu.
+func (s *Server) reconcilePeer(ctx context.Context, client proxymancommand.HandlerServiceClient, peer *types.Peer) error {
+	var errs []error
+	for _, tag := range append([]string(nil), peer.Inbounds...) {
+		inbound := s.config.Inbound(tag)
+		if inbound == nil {
+			peer.RemoveInbound(tag)
+			continue
+		}
+
+		// Add the user of the peer, unless the inbound has it.
+		if inbound.Proxy.IsMultiUser() {
+			if s.users[tag][peer.Email] {
+				continue
+			}
+			if err := s.removeUser(ctx, client, tag, peer.Email); err != nil {
+				errs = append(errs, fmt.Errorf("peer %s: %w", peer.Email, err))
+				continue
+			}
+			if err := s.addUser(ctx, client, tag, peer.User(inbound.Cipher)); err != nil {
+				errs = append(errs, fmt.Errorf("peer %s: %w", peer.Email, err))
+			}
+
+			continue
+		}
+
+		// Add the inbound dedicated to the peer, unless it exists.
+		if _, ok := s.applied[inbound.PeerTag(peer.Email)]; ok {
+			continue
+		}
+
+		config, err := inbound.PeerHandlerConfig(peer.Ports[tag], peer.User(inbound.Cipher))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("peer %s: %w", peer.Email, err))
+			continue
+		}
+		if err := s.removeInbound(ctx, client, config.Tag); err != nil {
+			errs = append(errs, fmt.Errorf("peer %s: %w", peer.Email, err))
+			continue
+		}
+		if err := s.addInbound(ctx, client, config); err != nil {
+			errs = append(errs, fmt.Errorf("peer %s: %w", peer.Email, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// removeUnknownPeers removes the users and the inbounds dedicated to peers which the server added to
+// the V2Ray core, but which the local collection does not know. The caller must hold mu.
+func (s *Server) removeUnknownPeers(ctx context.Context, client proxymancommand.HandlerServiceClient) error {
+	// known checks whether the peer with the given email exists and uses the inbound with the given tag.
+	known := func(email, tag string) bool {
+		peer := s.peers.Get(email)
+		return peer != nil && peer.HasInbound(tag)
+	}
+
+	var errs []error
+	for _, inbound := range s.config.Inbounds {
+		tag := inbound.Tag()
+
+		// Remove the unknown users of the inbound.
+		if inbound.Proxy.IsMultiUser() {
+			for email := range s.users[tag] {
+				if known(email, tag) {
+					continue
+				}
+				if err := s.removeUser(ctx, client, tag, email); err != nil {
+					errs = append(errs, fmt.Errorf("peer %s: %w", email, err))
+				}
+			}
+
+			continue
+		}
+
+		// Remove the unknown inbounds dedicated to peers, tagged "TAG/EMAIL".
+		prefix := tag + "/"
+		for peerTag := range s.applied {
+			email, ok := strings.CutPrefix(peerTag, prefix)
+			if !ok || known(email, tag) {
+				continue
+			}
+			if err := s.removeInbound(ctx, client, peerTag); err != nil {
+				errs = append(errs, fmt.Errorf("peer %s: %w", email, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,265 @@
+package v2ray
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/common/uuid"
+	"github.com/v2fly/v2ray-core/v5/features/inbound"
+	"github.com/v2fly/v2ray-core/v5/proxy"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+// freePorts returns the first of n consecutive ports which are free on the loopback interface.
+func freePorts(t *testing.T, n int) uint16 {
+	t.Helper()
+
+	for base := 20000; base < 60000; base += 97 {
+		ok := true
+		for i := 0; i < n && ok; i++ {
+			lis, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", base+i))
+			if err != nil {
+				ok = false
+				continue
+			}
+
+			_ = lis.Close()
+		}
+		if ok {
+			return uint16(base)
+		}
+	}
+
+	t.Fatalf("no %d consecutive free ports", n)
+	return 0
+}
+
+// newEmbeddedServer starts an embedded V2Ray server with the given inbounds, listening on the loopback interface.
+func newEmbeddedServer(t *testing.T, inbounds ...*types.InboundConfig) *Server {
+	t.Helper()
+
+	for _, item := range inbounds {
+		item.Listen = "127.0.0.1"
+	}
+
+	s := NewServerWithConfig(t.TempDir(), &ServerConfig{Inbounds: inbounds, Mode: ModeEmbedded})
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Stop() })
+
+	return s
+}
+
+// peerRequest encodes a request for a peer of the given proxy, made of the given byte.
+func peerRequest(t *testing.T, proxy types.Proxy, b byte) ([]byte, string) {
+	t.Helper()
+
+	uid, err := uuid.ParseBytes(bytes.Repeat([]byte{b}, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &types.PeerRequest{Proxy: proxy, UID: uid}
+	payload, err := req.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := (&sentinelsdk.PeerRequest{Type: sentinelsdk.ServiceTypeV2Ray, Payload: payload}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf, req.Email()
+}
+
+// inboundManager returns the inbound manager of the in-process V2Ray instance of the server.
+func inboundManager(t *testing.T, s *Server) inbound.Manager {
+	t.Helper()
+
+	instance, err := s.process.embeddedInstance()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := newEmbeddedHandlerServiceClient(instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client.inbound
+}
+
+// hasHandler checks whether the V2Ray instance of the server has the inbound with the given tag.
+func hasHandler(t *testing.T, s *Server, tag string) bool {
+	t.Helper()
+
+	_, err := inboundManager(t, s).GetHandler(context.Background(), tag)
+	return err == nil
+}
+
+// hasUser checks whether the VMess inbound of the V2Ray instance of the server with the given tag has the user
+// with the given email, by adding a probe user with the same email and removing it again.
+func hasUser(t *testing.T, s *Server, tag, email string) bool {
+	t.Helper()
+
+	handler, err := inboundManager(t, s).GetHandler(context.Background(), tag)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users, ok := handler.(proxy.GetInbound).GetInbound().(proxy.UserManager)
+	if !ok {
+		t.Fatalf("inbound %s does not manage users", tag)
+	}
+
+	user, err := (&types.Peer{Email: email, Proxy: types.ProxyVMess}).User(types.CipherUnspecified).ToMemoryUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := users.AddUser(context.Background(), user); err != nil {
+		return true
+	}
+	if err := users.RemoveUser(context.Background(), email); err != nil {
+		t.Fatal(err)
+	}
+
+	return false
+}
+
+func TestServer_AddPeer_RemovePeer_Idempotent(t *testing.T) {
+	vmess := &types.InboundConfig{Port: freePorts(t, 1), Proxy: types.ProxyVMess, Transport: types.TransportTCP}
+	s := newEmbeddedServer(t, vmess)
+
+	req, email := peerRequest(t, types.ProxyVMess, 0x01)
+
+	first, err := s.AddPeer(context.Background(), req)
+	if err != nil {
+		t.Fatalf("AddPeer() error = %v", err)
+	}
+
+	second, err := s.AddPeer(context.Background(), req)
+	if err != nil {
+		t.Fatalf("AddPeer() again error = %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatal("AddPeer() again returned a different response")
+	}
+	if !hasUser(t, s, vmess.Tag(), email) {
+		t.Fatal("AddPeer() did not add the user")
+	}
+
+	// Removing the peer twice, and removing a peer which never existed, succeeds.
+	unknown, _ := peerRequest(t, types.ProxyVMess, 0x02)
+	for _, buf := range [][]byte{req, req, unknown} {
+		if err := s.RemovePeer(context.Background(), buf); err != nil {
+			t.Fatalf("RemovePeer() error = %v", err)
+		}
+	}
+
+	if hasUser(t, s, vmess.Tag(), email) {
+		t.Fatal("RemovePeer() did not remove the user")
+	}
+	if s.PeerCount() != 0 || len(s.users[vmess.Tag()]) != 0 {
+		t.Fatalf("RemovePeer() left %d peers and users %v", s.PeerCount(), s.users[vmess.Tag()])
+	}
+}
+
+func TestServer_AddPeer_Rollback(t *testing.T) {
+	// The first inbound has room for two peers, the second one for a single peer.
+	a := &types.InboundConfig{Port: freePorts(t, 2), Ports: 2, Proxy: types.ProxyShadowsocks, Cipher: types.CipherAES128GCM, Transport: types.TransportTCP}
+	b := &types.InboundConfig{Port: freePorts(t, 1) + 10, Ports: 1, Proxy: types.ProxyShadowsocks, Cipher: types.CipherAES128GCM, Transport: types.TransportTCP}
+	s := newEmbeddedServer(t, a, b)
+
+	first, _ := peerRequest(t, types.ProxyShadowsocks, 0x01)
+	if _, err := s.AddPeer(context.Background(), first); err != nil {
+		t.Fatalf("AddPeer() error = %v", err)
+	}
+
+	// The second peer is added to the first inbound, then fails on the second one.
+	second, email := peerRequest(t, types.ProxyShadowsocks, 0x02)
+	if _, err := s.AddPeer(context.Background(), second); err == nil {
+		t.Fatal("AddPeer() error = nil, want an error for the full inbound")
+	}
+
+	if hasHandler(t, s, a.PeerTag(email)) {
+		t.Fatal("AddPeer() left the inbound of the failed peer")
+	}
+	if _, ok := s.applied[a.PeerTag(email)]; ok {
+		t.Fatal("AddPeer() left the inbound of the failed peer applied")
+	}
+	if s.PeerCount() != 1 {
+		t.Fatalf("PeerCount() = %d, want 1", s.PeerCount())
+	}
+}
+
+func TestServer_Reconcile(t *testing.T) {
+	vmess := &types.InboundConfig{Port: freePorts(t, 1), Proxy: types.ProxyVMess, Transport: types.TransportTCP}
+	ss := &types.InboundConfig{Port: freePorts(t, 2) + 10, Ports: 2, Proxy: types.ProxyShadowsocks, Cipher: types.CipherAES128GCM, Transport: types.TransportTCP}
+	s := newEmbeddedServer(t, vmess, ss)
+
+	known, knownEmail := peerRequest(t, types.ProxyVMess, 0x01)
+	unknown, unknownEmail := peerRequest(t, types.ProxyVMess, 0x02)
+	idle, idleEmail := peerRequest(t, types.ProxyShadowsocks, 0x03)
+	for _, buf := range [][]byte{known, unknown, idle} {
+		if _, err := s.AddPeer(context.Background(), buf); err != nil {
+			t.Fatalf("AddPeer() error = %v", err)
+		}
+	}
+
+	// Restart the V2Ray core, which loses every user and inbound of the peers.
+	if err := s.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if hasUser(t, s, vmess.Tag(), knownEmail) {
+		t.Fatal("Start() kept the user of the peer")
+	}
+
+	// Reconciling adds back the users and inbounds of the peers.
+	if err := s.Reconcile(context.Background()); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if !hasUser(t, s, vmess.Tag(), knownEmail) {
+		t.Fatal("Reconcile() did not add back the user of the peer")
+	}
+	if !hasHandler(t, s, ss.PeerTag(idleEmail)) {
+		t.Fatal("Reconcile() did not add back the inbound of the peer")
+	}
+
+	// Forget two peers, which never transferred any traffic, and reconcile again.
+	s.peers.Delete(unknownEmail)
+	s.peers.Delete(idleEmail)
+
+	if err := s.Reconcile(context.Background()); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if hasUser(t, s, vmess.Tag(), unknownEmail) {
+		t.Fatal("Reconcile() did not remove the unknown user")
+	}
+	if hasHandler(t, s, ss.PeerTag(idleEmail)) {
+		t.Fatal("Reconcile() did not remove the inbound of the unknown peer")
+	}
+	if !hasUser(t, s, vmess.Tag(), knownEmail) {
+		t.Fatal("Reconcile() removed the user of the known peer")
+	}
+
+	// Reconciling a reconciled server removes nothing, and keeps nothing unknown around.
+	if err := s.Reconcile(context.Background()); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if want := map[string]bool{knownEmail: true}; fmt.Sprint(s.users[vmess.Tag()]) != fmt.Sprint(want) {
+		t.Fatalf("users = %v, want %v", s.users[vmess.Tag()], want)
+	}
+}
@@ -0,0 +1,35 @@
+package v2ray
+
+import (
+	"fmt"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/service"
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// init registers the V2Ray server and client with the service registry.
+func init() {
+	service.RegisterServer(
+		sentinelsdk.ServiceTypeV2Ray,
+		func(homeDir string, config interface{}) (sentinelsdk.ServerService, error) {
+			c, ok := config.(*ServerConfig)
+			if !ok {
+				return nil, fmt.Errorf("invalid config type %T; expected %T", config, c)
+			}
+
+			return NewServerWithConfig(homeDir, c), nil
+		},
+	)
+
+	service.RegisterClient(
+		sentinelsdk.ServiceTypeV2Ray,
+		func(homeDir string, config interface{}) (sentinelsdk.ClientService, error) {
+			c, ok := config.(*ClientConfig)
+			if !ok {
+				return nil, fmt.Errorf("invalid config type %T; expected %T", config, c)
+			}
+
+			return NewClient(homeDir, c), nil
+		},
+	)
+}
@@ -0,0 +1,222 @@
+package v2ray
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	proxymancommand "github.com/v2fly/v2ray-core/v5/app/proxyman/command"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+const (
+	// CertificateCheckInterval represents the interval at which TLS certificate files are checked for changes.
+	CertificateCheckInterval = 30 * time.Second
+
+	// CertificateReloadTimeout represents the time allowed for reloading an inbound whose certificates changed,
+	// so an unresponsive V2Ray API does not stall the certificate watcher.
+	CertificateReloadTimeout = 15 * time.Second
+)
+
+// certificateModTime returns the latest modification time of the TLS files of the given inbound.
+func certificateModTime(inbound *types.InboundConfig) (t time.Time, err error) {
+	for _, name := range inbound.TLS.Files() {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if info.ModTime().After(t) {
+			t = info.ModTime()
+		}
+	}
+
+	return t, nil
+}
+
+// watchCertificates periodically checks the TLS certificate files of the inbounds and reloads
+// the inbounds whose certificates changed on disk, until the stop channel is closed.
+// Inbounds added at runtime are watched from the first check after they are added.
+// A failed reload is retried on the next check.
+func (s *Server) watchCertificates(stop <-chan struct{}) {
+	if s.config == nil {
+		return
+	}
+
+	// Record the modification times of the certificate files of the inbounds not seen before.
+	modTimes := make(map[string]time.Time)
+	record := func() {
+		for _, inbound := range s.Inbounds() {
+			if _, ok := modTimes[inbound.Tag()]; ok || inbound.TLS == nil {
+				continue
+			}
+
+			t, err := certificateModTime(inbound)
+			if err != nil {
+				continue
+			}
+
+			modTimes[inbound.Tag()] = t
+		}
+	}
+
+	record()
+
+	ticker := time.NewTicker(CertificateCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			for _, inbound := range s.Inbounds() {
+				if inbound.TLS == nil {
+					continue
+				}
+
+				// Skip the inbound if it was added since the last check, or if its certificate
+				// files are unreadable or unchanged.
+				last, ok := modTimes[inbound.Tag()]
+				if !ok {
+					continue
+				}
+
+				t, err := certificateModTime(inbound)
+				if err != nil || !t.After(last) {
+					continue
+				}
+
+				// Reload the inbound and record the new modification time on success.
+				ctx, cancel := context.WithTimeout(context.Background(), CertificateReloadTimeout)
+				err = s.ReloadInbound(ctx, inbound.Tag())
+				cancel()
+
+				if err != nil {
+					continue
+				}
+
+				modTimes[inbound.Tag()] = t
+			}
+
+			record()
+		}
+	}
+}
+
+// ReloadInbound recreates the inbound with the given tag, along with the inbounds dedicated to its peers,
+// so that changes to its TLS certificate files take effect. The users of the peers are added back
+// to the recreated inbounds. If the recreated inbound of a multi-user proxy cannot be added,
+// the inbound is restored with its previous configuration.
+func (s *Server) ReloadInbound(ctx context.Context, tag string) error {
+	// Check if the configuration is nil.
+	if s.config == nil {
+		return errors.New("nil config")
+	}
+
+	// Establish a gRPC client connection to the handler service before taking the lock,
+	// so an unresponsive V2Ray API does not block the other operations while dialing.
+	conn, client, err := s.handlerServiceClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Ensure the connection is closed when done.
+	defer func() {
+		if err = conn.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	// Serialize the change, so peers are not added or removed while the inbound is reloaded.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Look up the inbound with the given tag.
+	inbound := s.config.Inbound(tag)
+	if inbound == nil {
+		return fmt.Errorf("inbound %s: %w", tag, sentinelsdk.ErrNotFound)
+	}
+
+	// Collect the peers served by the inbound.
+	var peers []*types.Peer
+	if err := s.peers.Iterate(func(_ string, value *types.Peer) (bool, error) {
+		if value.HasInbound(inbound.Tag()) {
+			peers = append(peers, value)
+		}
+
+		return false, nil
+	}); err != nil {
+		return err
+	}
+
+	// Recreate the inbound dedicated to each peer, if the inbound serves a single user.
+	if !inbound.Proxy.IsMultiUser() {
+		for _, peer := range peers {
+			if err := s.reloadPeerInbound(ctx, client, inbound, peer); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Build the new configuration before removing the inbound, so a broken certificate
+	// does not leave the server without the inbound.
+	config, err := inbound.HandlerConfig()
+	if err != nil {
+		return err
+	}
+
+	// Replace the inbound with the new configuration, restoring the previous configuration on failure.
+	previous, ok := s.applied[inbound.Tag()]
+	if err := s.removeInbound(ctx, client, inbound.Tag()); err != nil {
+		return err
+	}
+	if err := s.addInbound(ctx, client, config); err != nil {
+		if !ok {
+			return err
+		}
+		if rerr := s.addInbound(ctx, client, previous); rerr != nil {
+			return errors.Join(err, fmt.Errorf("failed to restore inbound %s: %w", inbound.Tag(), rerr))
+		}
+
+		// Add the users of the peers back to the restored inbound.
+		for _, peer := range peers {
+			if rerr := s.addUser(ctx, client, inbound.Tag(), peer.User(inbound.Cipher)); rerr != nil {
+				return errors.Join(err, rerr)
+			}
+		}
+
+		return err
+	}
+
+	// Add the users of the peers back to the recreated inbound.
+	for _, peer := range peers {
+		if err := s.addUser(ctx, client, inbound.Tag(), peer.User(inbound.Cipher)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reloadPeerInbound recreates the inbound dedicated to the given peer.
+func (s *Server) reloadPeerInbound(ctx context.Context, client proxymancommand.HandlerServiceClient, inbound *types.InboundConfig, peer *types.Peer) error {
+	// Build the new configuration before removing the inbound.
+	config, err := inbound.PeerHandlerConfig(peer.Ports[inbound.Tag()], peer.User(inbound.Cipher))
+	if err != nil {
+		return err
+	}
+
+	// Replace the inbound with the new configuration.
+	if err := s.removeInbound(ctx, client, inbound.PeerTag(peer.Email)); err != nil {
+		return err
+	}
+
+	return s.addInbound(ctx, client, config)
+}
@@ -0,0 +1,103 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// AccessStatus represents whether a connection was accepted or rejected by V2Ray.
+type AccessStatus byte
+
+const (
+	// AccessStatusUnspecified represents an unspecified or unknown access status.
+	AccessStatusUnspecified AccessStatus = 0x00 + iota
+	// AccessStatusAccepted represents a connection accepted by V2Ray.
+	AccessStatusAccepted
+	// AccessStatusRejected represents a connection rejected by V2Ray, for example because of a failed authentication.
+	AccessStatusRejected
+)
+
+// String returns a human-readable string representation of the AccessStatus.
+func (s AccessStatus) String() string {
+	switch s {
+	case AccessStatusAccepted:
+		return "accepted"
+	case AccessStatusRejected:
+		return "rejected"
+	default:
+		return ""
+	}
+}
+
+// IsValid checks whether the AccessStatus is a known, specified access status.
+func (s AccessStatus) IsValid() bool {
+	return s.String() != ""
+}
+
+// AccessStatusFromString converts a string representation to the corresponding AccessStatus.
+func AccessStatusFromString(s string) AccessStatus {
+	switch s {
+	case "accepted":
+		return AccessStatusAccepted
+	case "rejected":
+		return AccessStatusRejected
+	default:
+		return AccessStatusUnspecified
+	}
+}
+
+// AccessRecord represents a single connection recorded in the V2Ray access log.
+type AccessRecord struct {
+	Destination string       `json:"destination"` // Destination is the requested destination, such as "tcp:example.com:443".
+	Detour      string       `json:"detour"`      // Detour is the tag of the outbound handling the connection, if any.
+	Email       string       `json:"email"`       // Email is the email of the peer, empty if the peer is unknown.
+	Reason      string       `json:"reason"`      // Reason is the reason a connection was rejected, if any.
+	Source      string       `json:"source"`      // Source is the address the connection came from.
+	Status      AccessStatus `json:"status"`      // Status tells whether the connection was accepted or rejected.
+	Time        time.Time    `json:"time"`        // Time is the time the connection was recorded.
+}
+
+// ParseAccessRecord parses a V2Ray access log message, without the timestamp prefix, into an AccessRecord.
+// The message has the format "SOURCE STATUS DESTINATION [DETOUR] REASON email: EMAIL", where the detour,
+// the reason and the email are optional. The time of the returned record is left for the caller to set.
+func ParseAccessRecord(s string) (*AccessRecord, error) {
+	record := &AccessRecord{}
+
+	// Extract the email, which always comes last.
+	if i := strings.LastIndex(s, " email: "); i >= 0 {
+		record.Email = s[i+len(" email: "):]
+		s = s[:i]
+	}
+
+	// Split the source, the status and the destination from the rest of the message.
+	fields := strings.SplitN(s, " ", 4)
+	if len(fields) < 3 {
+		return nil, errors.New("invalid access message")
+	}
+
+	record.Source = fields[0]
+	record.Destination = fields[2]
+
+	// Parse the status of the connection.
+	record.Status = AccessStatusFromString(fields[1])
+	if !record.Status.IsValid() {
+		return nil, errors.New("invalid access status")
+	}
+
+	if len(fields) == 4 {
+		rest := fields[3]
+
+		// Extract the detour, which is enclosed in square brackets.
+		if strings.HasPrefix(rest, "[") {
+			if i := strings.Index(rest, "]"); i >= 0 {
+				record.Detour = rest[1:i]
+				rest = strings.TrimPrefix(rest[i+1:], " ")
+			}
+		}
+
+		record.Reason = rest
+	}
+
+	return record, nil
+}
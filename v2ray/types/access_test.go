@@ -0,0 +1,180 @@
+package types_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/common/log"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+func TestParseAccessRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    *types.AccessRecord
+		wantErr bool
+	}{
+		{
+			name: "accepted tcp with detour and email",
+			s:    "203.0.113.5:51234 accepted tcp:www.example.com:443 [direct] email: AgEjRWeJq83vASNFZ4mrze8=",
+			want: &types.AccessRecord{
+				Destination: "tcp:www.example.com:443",
+				Detour:      "direct",
+				Email:       "AgEjRWeJq83vASNFZ4mrze8=",
+				Source:      "203.0.113.5:51234",
+				Status:      types.AccessStatusAccepted,
+			},
+		},
+		{
+			name: "accepted udp without detour",
+			s:    "203.0.113.5:40000 accepted udp:8.8.8.8:53 email: AQEjRWeJq83vASNFZ4mrze8=",
+			want: &types.AccessRecord{
+				Destination: "udp:8.8.8.8:53",
+				Email:       "AQEjRWeJq83vASNFZ4mrze8=",
+				Source:      "203.0.113.5:40000",
+				Status:      types.AccessStatusAccepted,
+			},
+		},
+		{
+			name: "accepted to the block outbound",
+			s:    "[2001:db8::5]:40000 accepted tcp:127.0.0.1:23 [block]",
+			want: &types.AccessRecord{
+				Destination: "tcp:127.0.0.1:23",
+				Detour:      "block",
+				Source:      "[2001:db8::5]:40000",
+				Status:      types.AccessStatusAccepted,
+			},
+		},
+		{
+			name: "rejected without destination",
+			s:    "203.0.113.9:50000 rejected  proxy/vmess/encoding: invalid user > proxy/vmess: Not Found",
+			want: &types.AccessRecord{
+				Reason: "proxy/vmess/encoding: invalid user > proxy/vmess: Not Found",
+				Source: "203.0.113.9:50000",
+				Status: types.AccessStatusRejected,
+			},
+		},
+		{
+			name: "rejected with destination",
+			s:    "tcp:203.0.113.9:50000 rejected tcp:example.com:80 proxy/trojan: failed to create request",
+			want: &types.AccessRecord{
+				Destination: "tcp:example.com:80",
+				Reason:      "proxy/trojan: failed to create request",
+				Source:      "tcp:203.0.113.9:50000",
+				Status:      types.AccessStatusRejected,
+			},
+		},
+		{
+			name:    "empty",
+			s:       "",
+			wantErr: true,
+		},
+		{
+			name:    "too few fields",
+			s:       "203.0.113.5:51234 accepted",
+			wantErr: true,
+		},
+		{
+			name:    "unknown status",
+			s:       "203.0.113.5:51234 dropped tcp:example.com:443",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := types.ParseAccessRecord(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAccessRecord() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseAccessRecord() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAccessRecord_AccessMessage(t *testing.T) {
+	source := net.TCPDestination(net.ParseAddress("203.0.113.5"), 51234)
+
+	tests := []struct {
+		name string
+		msg  *log.AccessMessage
+		want *types.AccessRecord
+	}{
+		{
+			name: "accepted tcp",
+			msg: &log.AccessMessage{
+				From:   source,
+				To:     net.TCPDestination(net.ParseAddress("example.com"), 443),
+				Status: log.AccessAccepted,
+				Detour: "direct",
+				Email:  "peer",
+			},
+			want: &types.AccessRecord{
+				Destination: "tcp:example.com:443",
+				Detour:      "direct",
+				Email:       "peer",
+				Source:      "tcp:203.0.113.5:51234",
+				Status:      types.AccessStatusAccepted,
+			},
+		},
+		{
+			name: "accepted udp",
+			msg: &log.AccessMessage{
+				From:   source,
+				To:     net.UDPDestination(net.ParseAddress("8.8.8.8"), 53),
+				Status: log.AccessAccepted,
+				Email:  "peer",
+			},
+			want: &types.AccessRecord{
+				Destination: "udp:8.8.8.8:53",
+				Email:       "peer",
+				Source:      "tcp:203.0.113.5:51234",
+				Status:      types.AccessStatusAccepted,
+			},
+		},
+		{
+			name: "rejected",
+			msg: &log.AccessMessage{
+				From:   source,
+				To:     "",
+				Status: log.AccessRejected,
+				Reason: errors.New("invalid user"),
+			},
+			want: &types.AccessRecord{
+				Reason: "invalid user",
+				Source: "tcp:203.0.113.5:51234",
+				Status: types.AccessStatusRejected,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := types.ParseAccessRecord(tt.msg.String())
+			if err != nil {
+				t.Fatalf("ParseAccessRecord(%q) error = %v", tt.msg.String(), err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseAccessRecord(%q) = %+v, want %+v", tt.msg.String(), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessStatus(t *testing.T) {
+	for _, status := range []types.AccessStatus{types.AccessStatusAccepted, types.AccessStatusRejected} {
+		if got := types.AccessStatusFromString(status.String()); got != status {
+			t.Errorf("AccessStatusFromString(%q) = %d, want %d", status.String(), got, status)
+		}
+	}
+
+	if types.AccessStatusUnspecified.IsValid() {
+		t.Error("AccessStatusUnspecified.IsValid() = true, want false")
+	}
+}
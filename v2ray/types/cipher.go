@@ -0,0 +1,68 @@
+package types
+
+import (
+	"github.com/v2fly/v2ray-core/v5/proxy/shadowsocks"
+)
+
+// Cipher represents different AEAD ciphers supported by the system.
+// The Shadowsocks 2022 edition ciphers are not supported, since the bundled v2ray-core
+// implements them only on the client side.
+type Cipher byte
+
+const (
+	// CipherUnspecified represents an unspecified or unknown cipher.
+	CipherUnspecified Cipher = 0x00 + iota
+	// CipherAES128GCM represents the AES-128-GCM cipher.
+	CipherAES128GCM
+	// CipherAES256GCM represents the AES-256-GCM cipher.
+	CipherAES256GCM
+	// CipherChaCha20Poly1305 represents the ChaCha20-Poly1305 cipher.
+	CipherChaCha20Poly1305
+)
+
+// String returns a human-readable string representation of the Cipher type.
+func (c Cipher) String() string {
+	switch c {
+	case CipherAES128GCM:
+		return "aes-128-gcm"
+	case CipherAES256GCM:
+		return "aes-256-gcm"
+	case CipherChaCha20Poly1305:
+		return "chacha20-poly1305"
+	default:
+		return ""
+	}
+}
+
+// IsValid checks whether the Cipher type is a known, specified cipher.
+func (c Cipher) IsValid() bool {
+	return c.String() != ""
+}
+
+// ShadowsocksCipherType returns the Shadowsocks cipher type corresponding to the Cipher type.
+func (c Cipher) ShadowsocksCipherType() shadowsocks.CipherType {
+	switch c {
+	case CipherAES128GCM:
+		return shadowsocks.CipherType_AES_128_GCM
+	case CipherAES256GCM:
+		return shadowsocks.CipherType_AES_256_GCM
+	case CipherChaCha20Poly1305:
+		return shadowsocks.CipherType_CHACHA20_POLY1305
+	default:
+		return shadowsocks.CipherType_UNKNOWN
+	}
+}
+
+// CipherFromString converts a string representation to the corresponding Cipher type.
+func CipherFromString(s string) Cipher {
+	switch s {
+	case "aes-128-gcm":
+		return CipherAES128GCM
+	case "aes-256-gcm":
+		return CipherAES256GCM
+	case "chacha20-poly1305":
+		return CipherChaCha20Poly1305
+	default:
+		return CipherUnspecified
+	}
+}
@@ -0,0 +1,42 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/proxy/shadowsocks"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+func TestCipher(t *testing.T) {
+	tests := []struct {
+		cipher      types.Cipher
+		name        string
+		shadowsocks shadowsocks.CipherType
+	}{
+		{types.CipherUnspecified, "", shadowsocks.CipherType_UNKNOWN},
+		{types.CipherAES128GCM, "aes-128-gcm", shadowsocks.CipherType_AES_128_GCM},
+		{types.CipherAES256GCM, "aes-256-gcm", shadowsocks.CipherType_AES_256_GCM},
+		{types.CipherChaCha20Poly1305, "chacha20-poly1305", shadowsocks.CipherType_CHACHA20_POLY1305},
+		{types.Cipher(0xFF), "", shadowsocks.CipherType_UNKNOWN},
+	}
+
+	for _, tt := range tests {
+		if got := tt.cipher.String(); got != tt.name {
+			t.Errorf("Cipher(%d).String() = %q, want %q", tt.cipher, got, tt.name)
+		}
+		if got := tt.cipher.IsValid(); got != (tt.name != "") {
+			t.Errorf("Cipher(%d).IsValid() = %t, want %t", tt.cipher, got, tt.name != "")
+		}
+		if got := tt.cipher.ShadowsocksCipherType(); got != tt.shadowsocks {
+			t.Errorf("Cipher(%d).ShadowsocksCipherType() = %s, want %s", tt.cipher, got, tt.shadowsocks)
+		}
+		if got := types.CipherFromString(tt.name); tt.name != "" && got != tt.cipher {
+			t.Errorf("CipherFromString(%q) = %d, want %d", tt.name, got, tt.cipher)
+		}
+	}
+
+	if got := types.CipherFromString("aes-128-cfb"); got != types.CipherUnspecified {
+		t.Errorf("CipherFromString(%q) = %d, want %d", "aes-128-cfb", got, types.CipherUnspecified)
+	}
+}
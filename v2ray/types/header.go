@@ -0,0 +1,93 @@
+package types
+
+import (
+	"github.com/v2fly/v2ray-core/v5/common/serial"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/headers/noop"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/headers/srtp"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/headers/tls"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/headers/utp"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/headers/wechat"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/headers/wireguard"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// PacketHeader represents different packet header obfuscations supported by the mKCP and QUIC transports.
+type PacketHeader byte
+
+const (
+	// PacketHeaderNone represents no packet header obfuscation.
+	PacketHeaderNone PacketHeader = 0x00 + iota
+	// PacketHeaderSRTP represents packets disguised as SRTP (video calls).
+	PacketHeaderSRTP
+	// PacketHeaderUTP represents packets disguised as uTP (BitTorrent).
+	PacketHeaderUTP
+	// PacketHeaderWechatVideo represents packets disguised as WeChat video calls.
+	PacketHeaderWechatVideo
+	// PacketHeaderDTLS represents packets disguised as DTLS 1.2.
+	PacketHeaderDTLS
+	// PacketHeaderWireGuard represents packets disguised as WireGuard.
+	PacketHeaderWireGuard
+)
+
+// String returns a human-readable string representation of the PacketHeader type.
+func (h PacketHeader) String() string {
+	switch h {
+	case PacketHeaderNone:
+		return "none"
+	case PacketHeaderSRTP:
+		return "srtp"
+	case PacketHeaderUTP:
+		return "utp"
+	case PacketHeaderWechatVideo:
+		return "wechat-video"
+	case PacketHeaderDTLS:
+		return "dtls"
+	case PacketHeaderWireGuard:
+		return "wireguard"
+	default:
+		return ""
+	}
+}
+
+// IsValid checks whether the PacketHeader type is a known packet header.
+func (h PacketHeader) IsValid() bool {
+	return h.String() != ""
+}
+
+// Settings generates an Any message containing the packet header settings.
+func (h PacketHeader) Settings() *anypb.Any {
+	switch h {
+	case PacketHeaderNone:
+		return serial.ToTypedMessage(&noop.Config{})
+	case PacketHeaderSRTP:
+		return serial.ToTypedMessage(&srtp.Config{})
+	case PacketHeaderUTP:
+		return serial.ToTypedMessage(&utp.Config{})
+	case PacketHeaderWechatVideo:
+		return serial.ToTypedMessage(&wechat.VideoConfig{})
+	case PacketHeaderDTLS:
+		return serial.ToTypedMessage(&tls.PacketConfig{})
+	case PacketHeaderWireGuard:
+		return serial.ToTypedMessage(&wireguard.WireguardConfig{})
+	default:
+		return nil
+	}
+}
+
+// PacketHeaderFromString converts a string representation to the corresponding PacketHeader type.
+func PacketHeaderFromString(s string) PacketHeader {
+	switch s {
+	case "srtp":
+		return PacketHeaderSRTP
+	case "utp":
+		return PacketHeaderUTP
+	case "wechat-video":
+		return PacketHeaderWechatVideo
+	case "dtls":
+		return PacketHeaderDTLS
+	case "wireguard":
+		return PacketHeaderWireGuard
+	default:
+		return PacketHeaderNone
+	}
+}
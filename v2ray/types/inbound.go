@@ -0,0 +1,196 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/app/proxyman"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/common/serial"
+	"github.com/v2fly/v2ray-core/v5/transport/internet"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// InboundConfig represents the configuration of a server-side inbound handler.
+type InboundConfig struct {
+	Listen    string     // Listen is the IP address the inbound listens on.
+	Port      uint16     // Port is the port the inbound listens on.
+	Ports     uint16     // Ports is the number of ports, starting at Port, reserved for per-peer inbounds.
+	Proxy     Proxy      // Proxy is the proxy protocol served by the inbound.
+	Cipher    Cipher     // Cipher is the cipher used by the proxy, if it requires one.
+	Transport Transport  // Transport is the transport protocol used by the inbound.
+	TLS       *TLSConfig // TLS holds the TLS settings, or nil if TLS is disabled.
+	Sniffing  bool       // Sniffing enables the detection of the application protocol of the traffic.
+
+	// TransportSettings holds the settings of the transport protocol, or nil for the defaults.
+	TransportSettings *TransportSettings
+}
+
+// Tag returns the tag of the inbound handler, derived from the proxy, the transport and the port,
+// so a proxy can be served by several inbounds.
+func (c *InboundConfig) Tag() string {
+	return fmt.Sprintf("%s/%s/%d", c.Proxy.Tag(), c.Transport, c.Port)
+}
+
+// PeerTag returns the tag of the inbound handler dedicated to the peer with the given email.
+func (c *InboundConfig) PeerTag(email string) string {
+	return fmt.Sprintf("%s/%s", c.Tag(), email)
+}
+
+// PortRange returns the first and the last port reserved for the inbound.
+// Multi-user proxies are served on a single port, while the other proxies are served
+// on a dedicated port per peer.
+func (c *InboundConfig) PortRange() (uint16, uint16) {
+	if c.Proxy.IsMultiUser() {
+		return c.Port, c.Port
+	}
+
+	return c.Port, c.Port + c.Ports - 1
+}
+
+// Validate checks whether the InboundConfig is valid.
+func (c *InboundConfig) Validate() error {
+	if c.Port == 0 && c.Transport != TransportDomainSocket {
+		return errors.New("port cannot be zero")
+	}
+	if !c.Proxy.IsValid() {
+		return fmt.Errorf("invalid proxy %d", c.Proxy)
+	}
+	if !c.Proxy.IsMultiUser() {
+		if c.Transport == TransportDomainSocket {
+			return fmt.Errorf("proxy %s does not support transport %s", c.Proxy, c.Transport)
+		}
+		if c.Ports == 0 {
+			return fmt.Errorf("proxy %s requires ports for per-peer inbounds", c.Proxy)
+		}
+		if uint32(c.Port)+uint32(c.Ports)-1 > 65535 {
+			return errors.New("port range exceeds the maximum port")
+		}
+	}
+	if c.Proxy.RequiresCipher() && !c.Cipher.IsValid() {
+		return fmt.Errorf("proxy %s requires a valid cipher", c.Proxy)
+	}
+	if c.Proxy.RequiresTLS() && c.TLS == nil {
+		return fmt.Errorf("proxy %s requires tls", c.Proxy)
+	}
+	if c.Transport.RequiresTLS() && c.TLS == nil {
+		return fmt.Errorf("transport %s requires tls", c.Transport)
+	}
+	if c.TLS != nil {
+		if err := c.TLS.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// streamSettings builds the stream settings of the inbound handler.
+func (c *InboundConfig) streamSettings() (*internet.StreamConfig, error) {
+	settings, err := c.Transport.StreamSettings(c.TransportSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attach the TLS security settings if TLS is enabled.
+	if c.TLS != nil {
+		security, err := c.TLS.ServerSecuritySettings()
+		if err != nil {
+			return nil, err
+		}
+
+		settings.SecurityType = c.TLS.SecurityType()
+		settings.SecuritySettings = []*anypb.Any{security}
+	}
+
+	return settings, nil
+}
+
+// handlerConfig builds a v2ray inbound handler configuration with the given tag, port and users.
+func (c *InboundConfig) handlerConfig(tag string, port uint16, users []*protocol.User) (*core.InboundHandlerConfig, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Build the stream settings, including TLS if enabled.
+	streamSettings, err := c.streamSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	// Default to listening on all interfaces.
+	listen := c.Listen
+	if listen == "" {
+		listen = "0.0.0.0"
+	}
+
+	receiverSettings := &proxyman.ReceiverConfig{
+		PortRange:      net.SinglePortRange(net.Port(port)),
+		Listen:         net.NewIPOrDomain(net.ParseAddress(listen)),
+		StreamSettings: streamSettings,
+	}
+
+	// A domain socket inbound listens on the socket path instead of a port.
+	if c.Transport == TransportDomainSocket {
+		receiverSettings.PortRange = nil
+	}
+
+	// Detect the application protocol of the traffic, so it can be matched by routing rules.
+	if c.Sniffing {
+		receiverSettings.SniffingSettings = &proxyman.SniffingConfig{
+			Enabled: true,
+		}
+	}
+
+	return &core.InboundHandlerConfig{
+		Tag:              tag,
+		ReceiverSettings: serial.ToTypedMessage(receiverSettings),
+		ProxySettings:    c.Proxy.InboundSettings(users),
+	}, nil
+}
+
+// HandlerConfig builds the v2ray inbound handler configuration of a multi-user proxy.
+// The inbound is created without any users; users are added at runtime with AddPeer.
+func (c *InboundConfig) HandlerConfig() (*core.InboundHandlerConfig, error) {
+	if !c.Proxy.IsMultiUser() {
+		return nil, fmt.Errorf("proxy %s does not support multiple users", c.Proxy)
+	}
+
+	return c.handlerConfig(c.Tag(), c.Port, nil)
+}
+
+// PeerHandlerConfig builds the v2ray inbound handler configuration dedicated to a single peer,
+// listening on the given port.
+func (c *InboundConfig) PeerHandlerConfig(port uint16, user *protocol.User) (*core.InboundHandlerConfig, error) {
+	if first, last := c.PortRange(); port < first || port > last {
+		return nil, fmt.Errorf("port %d is out of range [%d, %d]", port, first, last)
+	}
+
+	return c.handlerConfig(c.PeerTag(user.Email), port, []*protocol.User{user})
+}
+
+// Info returns the information a client needs to connect to the inbound, including the range of ports
+// reserved for per-peer inbounds.
+func (c *InboundConfig) Info() *InboundInfo {
+	info := &InboundInfo{
+		Proxy:             c.Proxy,
+		Transport:         c.Transport,
+		TLS:               c.TLS != nil,
+		Port:              c.Port,
+		Cipher:            c.Cipher,
+		TransportSettings: c.TransportSettings,
+	}
+
+	if !c.Proxy.IsMultiUser() {
+		info.Ports = c.Ports
+	}
+
+	if c.TLS != nil {
+		info.ALPN = c.TLS.ALPN
+		info.ServerName = c.TLS.ServerName
+	}
+
+	return info
+}
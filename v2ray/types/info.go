@@ -0,0 +1,363 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/v2fly/v2ray-core/v5/common/uuid"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// InfoVersion represents the current version of the binary encoding of Info.
+//
+// It is laid out as follows, with multi-byte integers in big-endian byte order:
+//
+//	version (1 byte) | count (1 byte) | count inbound entries
+//
+// and each inbound entry is laid out as:
+//
+//	length (2 bytes) | proxy (1 byte) | transport (1 byte) | flags (1 byte) | port (2 bytes) | ports (2 bytes) |
+//	cipher (1 byte) | packet header (1 byte) | security (1 byte) | server name | path | key | seed | service name |
+//	host count (1 byte) | hosts | header count (1 byte) | header names and values | alpn count (1 byte) | alpn
+//
+// where length is the number of bytes following it in the entry, ports is the number of ports reserved for
+// per-peer inbounds or 0, bit 0 of flags is set if TLS is enabled,
+// bit 1 of flags is set if the transport settings are present, and each string is prefixed by its length
+// in 1 byte. Headers are sorted by name. Future fields are appended to the end of an entry; decoders skip
+// the bytes they do not understand.
+const InfoVersion = 0x01
+
+const (
+	// inboundInfoLen represents the minimum length of an inbound entry, excluding its length bytes.
+	inboundInfoLen = 1 + 1 + 1 + 2 + 2 + 1 + 1 + 1 + 5 + 3
+
+	// inboundInfoFlagTLS represents the flag set when TLS is enabled on an inbound.
+	inboundInfoFlagTLS = 1 << 0
+
+	// inboundInfoFlagTransportSettings represents the flag set when an inbound has transport settings.
+	inboundInfoFlagTransportSettings = 1 << 1
+)
+
+// InboundInfo represents the information a client needs to connect to an inbound of the server.
+type InboundInfo struct {
+	Proxy             Proxy              // Proxy is the proxy protocol served by the inbound.
+	Transport         Transport          // Transport is the transport protocol used by the inbound.
+	TLS               bool               // TLS is true if TLS is enabled on the inbound.
+	Port              uint16             // Port is the port of the inbound, or the first port of the per-peer inbounds.
+	Ports             uint16             // Ports is the number of ports reserved for per-peer inbounds, or 0 for a shared inbound.
+	Cipher            Cipher             // Cipher is the cipher used by the proxy, if it requires one.
+	ALPN              []string           // ALPN is the list of TLS application protocols, if TLS is enabled.
+	ServerName        string             // ServerName is the TLS server name, or empty to use the address of the server.
+	TransportSettings *TransportSettings // TransportSettings are the settings of the transport protocol, if any.
+}
+
+// OutboundConfig builds the client-side outbound configuration for connecting to the inbound
+// of the server at the given address with the given UUID, on the port of the InboundInfo.
+//
+// The server info publishes the range of ports of the per-peer inbounds, such as those of Shadowsocks,
+// which does not tell which port serves a given peer. For those inbounds, the InboundInfo must be the one
+// of the PeerResponse, whose port is the one assigned to the peer.
+func (i *InboundInfo) OutboundConfig(addr string, uid uuid.UUID) *OutboundConfig {
+	config := &OutboundConfig{
+		Address:           addr,
+		Port:              i.Port,
+		Proxy:             i.Proxy,
+		Cipher:            i.Cipher,
+		Transport:         i.Transport,
+		UID:               uid,
+		TransportSettings: i.TransportSettings,
+	}
+
+	if i.TLS {
+		// Verify the certificate against the published server name, falling back to the address.
+		serverName := i.ServerName
+		if serverName == "" {
+			serverName = addr
+		}
+
+		config.TLS = &TLSConfig{
+			ALPN:       i.ALPN,
+			ServerName: serverName,
+		}
+	}
+
+	return config
+}
+
+// Info represents the information about a V2Ray server published to clients.
+type Info struct {
+	Inbounds []*InboundInfo // Inbounds is the list of inbounds served to peers.
+}
+
+// Inbound returns the first inbound serving the given proxy, or nil if the proxy is not served.
+func (i *Info) Inbound(proxy Proxy) *InboundInfo {
+	for _, inbound := range i.Inbounds {
+		if inbound.Proxy == proxy {
+			return inbound
+		}
+	}
+
+	return nil
+}
+
+// MarshalBinary encodes the Info in the binary format of the current InfoVersion.
+func (i *Info) MarshalBinary() ([]byte, error) {
+	if len(i.Inbounds) > 255 {
+		return nil, fmt.Errorf("too many inbounds; expected at most 255, got %d", len(i.Inbounds))
+	}
+
+	buf := make([]byte, 0, 2+len(i.Inbounds)*(2+inboundInfoLen))
+	buf = append(buf, InfoVersion, byte(len(i.Inbounds)))
+
+	for _, inbound := range i.Inbounds {
+		entry, err := inbound.marshal()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode inbound %s/%s: %w", inbound.Proxy, inbound.Transport, err)
+		}
+		if len(entry) > 0xFFFF {
+			return nil, fmt.Errorf("inbound entry too long; expected at most %d bytes, got %d", 0xFFFF, len(entry))
+		}
+
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(entry)))
+		buf = append(buf, entry...)
+	}
+
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the Info from the binary format of the current InfoVersion.
+// It accepts entries with fields added by later revisions of the version.
+func (i *Info) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2 {
+		return fmt.Errorf("%w: invalid info length; expected at least 2, got %d", sentinelsdk.ErrInvalidPayload, len(buf))
+	}
+	if buf[0] != InfoVersion {
+		return fmt.Errorf("%w: unsupported info version %d", sentinelsdk.ErrInvalidPayload, buf[0])
+	}
+
+	count := int(buf[1])
+	buf = buf[2:]
+
+	inbounds := make([]*InboundInfo, 0, count)
+	for j := 0; j < count; j++ {
+		if len(buf) < 2 {
+			return fmt.Errorf("%w: unexpected end of info", sentinelsdk.ErrInvalidPayload)
+		}
+
+		// Read the entry, skipping its length prefix.
+		length := int(binary.BigEndian.Uint16(buf[:2]))
+		if length < inboundInfoLen || len(buf) < 2+length {
+			return fmt.Errorf("%w: invalid inbound entry length %d", sentinelsdk.ErrInvalidPayload, length)
+		}
+
+		entry := buf[2 : 2+length]
+		buf = buf[2+length:]
+
+		inbound := &InboundInfo{}
+		if err := inbound.unmarshal(entry); err != nil {
+			return err
+		}
+
+		inbounds = append(inbounds, inbound)
+	}
+
+	if len(buf) != 0 {
+		return fmt.Errorf("%w: unexpected %d trailing bytes in info", sentinelsdk.ErrInvalidPayload, len(buf))
+	}
+
+	i.Inbounds = inbounds
+	return nil
+}
+
+// marshal encodes the inbound entry of the current InfoVersion, excluding its length prefix.
+func (i *InboundInfo) marshal() ([]byte, error) {
+	var flags byte
+	if i.TLS {
+		flags |= inboundInfoFlagTLS
+	}
+
+	settings := i.TransportSettings
+	if settings != nil {
+		flags |= inboundInfoFlagTransportSettings
+	} else {
+		settings = &TransportSettings{}
+	}
+
+	buf := make([]byte, 0, inboundInfoLen)
+	buf = append(buf, byte(i.Proxy), byte(i.Transport), flags)
+	buf = binary.BigEndian.AppendUint16(buf, i.Port)
+	buf = binary.BigEndian.AppendUint16(buf, i.Ports)
+	buf = append(buf, byte(i.Cipher), byte(settings.PacketHeader), byte(settings.Security))
+
+	var err error
+	for _, s := range []string{i.ServerName, settings.Path, settings.Key, settings.Seed, settings.ServiceName} {
+		if buf, err = appendString(buf, s); err != nil {
+			return nil, err
+		}
+	}
+
+	if buf, err = appendStrings(buf, settings.Host); err != nil {
+		return nil, err
+	}
+
+	// Encode the headers sorted by name, so that equal settings are encoded identically.
+	names := make([]string, 0, len(settings.Headers))
+	for name := range settings.Headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	headers := make([]string, 0, 2*len(names))
+	for _, name := range names {
+		headers = append(headers, name, settings.Headers[name])
+	}
+
+	if len(names) > 255 {
+		return nil, fmt.Errorf("too many headers; expected at most 255, got %d", len(names))
+	}
+
+	buf = append(buf, byte(len(names)))
+	for _, s := range headers {
+		if buf, err = appendString(buf, s); err != nil {
+			return nil, err
+		}
+	}
+
+	if buf, err = appendStrings(buf, i.ALPN); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// unmarshal decodes the inbound entry, excluding its length prefix.
+// The length of the entry must be at least inboundInfoLen.
+func (i *InboundInfo) unmarshal(entry []byte) error {
+	flags := entry[2]
+
+	i.Proxy = Proxy(entry[0])
+	i.Transport = Transport(entry[1])
+	i.TLS = flags&inboundInfoFlagTLS != 0
+	i.Port = binary.BigEndian.Uint16(entry[3:5])
+	i.Ports = binary.BigEndian.Uint16(entry[5:7])
+	i.Cipher = Cipher(entry[7])
+
+	settings := &TransportSettings{
+		PacketHeader: PacketHeader(entry[8]),
+		Security:     Cipher(entry[9]),
+	}
+
+	r := &infoReader{buf: entry[10:]}
+	i.ServerName = r.string()
+	settings.Path = r.string()
+	settings.Key = r.string()
+	settings.Seed = r.string()
+	settings.ServiceName = r.string()
+	settings.Host = r.strings()
+
+	count := r.byte()
+	for j := 0; j < int(count) && r.err == nil; j++ {
+		name, value := r.string(), r.string()
+		if settings.Headers == nil {
+			settings.Headers = make(map[string]string)
+		}
+
+		settings.Headers[name] = value
+	}
+
+	i.ALPN = r.strings()
+	if r.err != nil {
+		return r.err
+	}
+
+	if flags&inboundInfoFlagTransportSettings != 0 {
+		i.TransportSettings = settings
+	}
+
+	return nil
+}
+
+// appendString appends the string s prefixed by its length in 1 byte.
+func appendString(buf []byte, s string) ([]byte, error) {
+	if len(s) > 255 {
+		return nil, fmt.Errorf("string %q too long; expected at most 255 bytes, got %d", s, len(s))
+	}
+
+	buf = append(buf, byte(len(s)))
+	return append(buf, s...), nil
+}
+
+// appendStrings appends the list of strings prefixed by its count in 1 byte.
+func appendStrings(buf []byte, items []string) ([]byte, error) {
+	if len(items) > 255 {
+		return nil, fmt.Errorf("too many strings; expected at most 255, got %d", len(items))
+	}
+
+	buf = append(buf, byte(len(items)))
+	for _, s := range items {
+		var err error
+		if buf, err = appendString(buf, s); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf, nil
+}
+
+// infoReader reads the fields of an inbound entry, recording the first error encountered.
+type infoReader struct {
+	buf []byte
+	err error
+}
+
+// byte reads a single byte, or returns zero if the entry is exhausted.
+func (r *infoReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.buf) < 1 {
+		r.err = fmt.Errorf("%w: unexpected end of inbound entry", sentinelsdk.ErrInvalidPayload)
+		return 0
+	}
+
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+
+	return b
+}
+
+// string reads a string prefixed by its length in 1 byte.
+func (r *infoReader) string() string {
+	length := int(r.byte())
+	if r.err != nil {
+		return ""
+	}
+	if len(r.buf) < length {
+		r.err = fmt.Errorf("%w: unexpected end of inbound entry", sentinelsdk.ErrInvalidPayload)
+		return ""
+	}
+
+	s := string(r.buf[:length])
+	r.buf = r.buf[length:]
+
+	return s
+}
+
+// strings reads a list of strings prefixed by its count in 1 byte, returning nil for an empty list.
+func (r *infoReader) strings() []string {
+	count := int(r.byte())
+
+	var items []string
+	for j := 0; j < count && r.err == nil; j++ {
+		items = append(items, r.string())
+	}
+
+	if r.err != nil {
+		return nil
+	}
+
+	return items
+}
@@ -0,0 +1,323 @@
+package types_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/common/uuid"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+func TestInfo_MarshalBinary(t *testing.T) {
+	tests := []struct {
+		name string
+		info *types.Info
+	}{
+		{
+			name: "empty",
+			info: &types.Info{Inbounds: []*types.InboundInfo{}},
+		},
+		{
+			name: "vmess over tcp",
+			info: &types.Info{
+				Inbounds: []*types.InboundInfo{
+					{Proxy: types.ProxyVMess, Transport: types.TransportTCP, Port: 443},
+				},
+			},
+		},
+		{
+			name: "trojan over websocket with tls",
+			info: &types.Info{
+				Inbounds: []*types.InboundInfo{
+					{
+						Proxy:      types.ProxyTrojan,
+						Transport:  types.TransportWebSocket,
+						TLS:        true,
+						Port:       8443,
+						ALPN:       []string{"h2", "http/1.1"},
+						ServerName: "node.example.com",
+						TransportSettings: &types.TransportSettings{
+							Headers: map[string]string{"Host": "cdn.example.com", "User-Agent": "test"},
+							Path:    "/ws",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "multiple inbounds",
+			info: &types.Info{
+				Inbounds: []*types.InboundInfo{
+					{
+						Proxy:     types.ProxyShadowsocks,
+						Transport: types.TransportMKCP,
+						Port:      7000,
+						Ports:     100,
+						Cipher:    types.CipherChaCha20Poly1305,
+						TransportSettings: &types.TransportSettings{
+							PacketHeader: types.PacketHeaderWireGuard,
+							Seed:         "seed",
+						},
+					},
+					{
+						Proxy:     types.ProxyVMess,
+						Transport: types.TransportQUIC,
+						TLS:       true,
+						Port:      7001,
+						TransportSettings: &types.TransportSettings{
+							Key:          "key",
+							PacketHeader: types.PacketHeaderSRTP,
+							Security:     types.CipherAES128GCM,
+						},
+					},
+					{
+						Proxy:     types.ProxyVMess,
+						Transport: types.TransportGRPC,
+						TLS:       true,
+						Port:      7002,
+						TransportSettings: &types.TransportSettings{
+							Host:        []string{"grpc.example.com"},
+							ServiceName: "tunnel",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := tt.info.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			if buf[0] != types.InfoVersion {
+				t.Fatalf("MarshalBinary() version = %d, want %d", buf[0], types.InfoVersion)
+			}
+
+			var got types.Info
+			if err := got.UnmarshalBinary(buf); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if !reflect.DeepEqual(&got, tt.info) {
+				t.Fatalf("UnmarshalBinary() = %+v, want %+v", got.Inbounds, tt.info.Inbounds)
+			}
+		})
+	}
+}
+
+func TestInfo_MarshalBinary_TooLong(t *testing.T) {
+	long := make([]byte, 256)
+	for i := range long {
+		long[i] = 'a'
+	}
+
+	info := &types.Info{
+		Inbounds: []*types.InboundInfo{
+			{
+				Proxy:             types.ProxyVMess,
+				Transport:         types.TransportWebSocket,
+				TransportSettings: &types.TransportSettings{Path: string(long)},
+			},
+		},
+	}
+
+	if _, err := info.MarshalBinary(); err == nil {
+		t.Fatal("MarshalBinary() error = nil, want an error for a path longer than 255 bytes")
+	}
+}
+
+func TestInfo_UnmarshalBinary(t *testing.T) {
+	valid, err := (&types.Info{
+		Inbounds: []*types.InboundInfo{
+			{
+				Proxy:             types.ProxyVMess,
+				Transport:         types.TransportWebSocket,
+				Port:              443,
+				TransportSettings: &types.TransportSettings{Path: "/ws"},
+			},
+		},
+	}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	// Extend the entry with a field added by a later revision of the version.
+	extended := append([]byte{}, valid...)
+	extended[3]++
+	extended = append(extended, 0xFF)
+
+	tests := []struct {
+		name    string
+		buf     []byte
+		want    *types.Info
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			buf:     nil,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported version",
+			buf:     []byte{types.InfoVersion + 1, 0x00},
+			wantErr: true,
+		},
+		{
+			name:    "missing entry",
+			buf:     []byte{types.InfoVersion, 0x01},
+			wantErr: true,
+		},
+		{
+			name:    "truncated",
+			buf:     valid[:len(valid)-1],
+			wantErr: true,
+		},
+		{
+			name:    "trailing bytes",
+			buf:     append(append([]byte{}, valid...), 0x00),
+			wantErr: true,
+		},
+		{
+			name: "unknown fields",
+			buf:  extended,
+			want: &types.Info{
+				Inbounds: []*types.InboundInfo{
+					{
+						Proxy:             types.ProxyVMess,
+						Transport:         types.TransportWebSocket,
+						Port:              443,
+						TransportSettings: &types.TransportSettings{Path: "/ws"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got types.Info
+
+			err := got.UnmarshalBinary(tt.buf)
+			if tt.wantErr {
+				if !errors.Is(err, sentinelsdk.ErrInvalidPayload) {
+					t.Fatalf("UnmarshalBinary() error = %v, want %v", err, sentinelsdk.ErrInvalidPayload)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if !reflect.DeepEqual(&got, tt.want) {
+				t.Fatalf("UnmarshalBinary() = %+v, want %+v", got.Inbounds, tt.want.Inbounds)
+			}
+		})
+	}
+}
+
+func TestInboundConfig_Info(t *testing.T) {
+	tests := []struct {
+		name    string
+		inbound *types.InboundConfig
+		want    *types.InboundInfo
+	}{
+		{
+			name:    "shared inbound",
+			inbound: &types.InboundConfig{Port: 443, Ports: 10, Proxy: types.ProxyVMess, Transport: types.TransportTCP},
+			want:    &types.InboundInfo{Proxy: types.ProxyVMess, Transport: types.TransportTCP, Port: 443},
+		},
+		{
+			name: "per-peer inbounds",
+			inbound: &types.InboundConfig{
+				Port:      7000,
+				Ports:     100,
+				Proxy:     types.ProxyShadowsocks,
+				Cipher:    types.CipherAES128GCM,
+				Transport: types.TransportTCP,
+			},
+			want: &types.InboundInfo{
+				Proxy:     types.ProxyShadowsocks,
+				Transport: types.TransportTCP,
+				Port:      7000,
+				Ports:     100,
+				Cipher:    types.CipherAES128GCM,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.inbound.Info(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Info() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInboundInfo_OutboundConfig(t *testing.T) {
+	uid := uuid.New()
+	settings := &types.TransportSettings{ServiceName: "tunnel"}
+
+	tests := []struct {
+		name string
+		info *types.InboundInfo
+		want *types.OutboundConfig
+	}{
+		{
+			name: "without tls",
+			info: &types.InboundInfo{Proxy: types.ProxyVMess, Transport: types.TransportTCP, Port: 443},
+			want: &types.OutboundConfig{
+				Address:   "203.0.113.1",
+				Port:      443,
+				Proxy:     types.ProxyVMess,
+				Transport: types.TransportTCP,
+				UID:       uid,
+			},
+		},
+		{
+			name: "tls without server name",
+			info: &types.InboundInfo{Proxy: types.ProxyTrojan, Transport: types.TransportTCP, TLS: true, Port: 443},
+			want: &types.OutboundConfig{
+				Address:   "203.0.113.1",
+				Port:      443,
+				Proxy:     types.ProxyTrojan,
+				Transport: types.TransportTCP,
+				TLS:       &types.TLSConfig{ServerName: "203.0.113.1"},
+				UID:       uid,
+			},
+		},
+		{
+			name: "tls with server name and transport settings",
+			info: &types.InboundInfo{
+				Proxy:             types.ProxyVMess,
+				Transport:         types.TransportGRPC,
+				TLS:               true,
+				Port:              443,
+				ALPN:              []string{"h2"},
+				ServerName:        "node.example.com",
+				TransportSettings: settings,
+			},
+			want: &types.OutboundConfig{
+				Address:           "203.0.113.1",
+				Port:              443,
+				Proxy:             types.ProxyVMess,
+				Transport:         types.TransportGRPC,
+				TLS:               &types.TLSConfig{ALPN: []string{"h2"}, ServerName: "node.example.com"},
+				UID:               uid,
+				TransportSettings: settings,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.info.OutboundConfig("203.0.113.1", uid)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("OutboundConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/app/proxyman"
+	"github.com/v2fly/v2ray-core/v5/common/serial"
+	"github.com/v2fly/v2ray-core/v5/common/uuid"
+	"github.com/v2fly/v2ray-core/v5/transport/internet"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// OutboundConfig represents the configuration of a client-side outbound handler.
+type OutboundConfig struct {
+	Address   string     // Address is the IP address or domain of the server.
+	Port      uint16     // Port is the port of the server inbound.
+	Proxy     Proxy      // Proxy is the proxy protocol served by the server inbound.
+	Cipher    Cipher     // Cipher is the cipher used by the proxy, if it requires one.
+	Transport Transport  // Transport is the transport protocol used by the server inbound.
+	TLS       *TLSConfig // TLS holds the TLS settings, or nil if TLS is disabled.
+	UID       uuid.UUID  // UID is the UUID the client was added to the server with.
+
+	// TransportSettings holds the settings of the transport protocol, or nil for the defaults.
+	TransportSettings *TransportSettings
+}
+
+// Tag returns the tag of the outbound handler.
+func (c *OutboundConfig) Tag() string {
+	return c.Proxy.Tag()
+}
+
+// Validate checks whether the OutboundConfig is valid.
+func (c *OutboundConfig) Validate() error {
+	if c.Address == "" {
+		return errors.New("address cannot be empty")
+	}
+	if c.Port == 0 && c.Transport != TransportDomainSocket {
+		return errors.New("port cannot be zero")
+	}
+	if !c.Proxy.IsValid() {
+		return fmt.Errorf("invalid proxy %d", c.Proxy)
+	}
+	if c.Proxy.RequiresCipher() && !c.Cipher.IsValid() {
+		return fmt.Errorf("proxy %s requires a valid cipher", c.Proxy)
+	}
+	if c.Proxy.RequiresTLS() && c.TLS == nil {
+		return fmt.Errorf("proxy %s requires tls", c.Proxy)
+	}
+	if c.Transport.RequiresTLS() && c.TLS == nil {
+		return fmt.Errorf("transport %s requires tls", c.Transport)
+	}
+
+	return nil
+}
+
+// streamSettings builds the stream settings of the outbound handler.
+func (c *OutboundConfig) streamSettings() (*internet.StreamConfig, error) {
+	settings, err := c.Transport.StreamSettings(c.TransportSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attach the TLS security settings if TLS is enabled.
+	if c.TLS != nil {
+		settings.SecurityType = c.TLS.SecurityType()
+		settings.SecuritySettings = []*anypb.Any{c.TLS.ClientSecuritySettings()}
+	}
+
+	return settings, nil
+}
+
+// HandlerConfig builds the v2ray outbound handler configuration.
+func (c *OutboundConfig) HandlerConfig() (*core.OutboundHandlerConfig, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Build the stream settings, including TLS if enabled.
+	streamSettings, err := c.streamSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	return &core.OutboundHandlerConfig{
+		Tag: c.Tag(),
+		SenderSettings: serial.ToTypedMessage(
+			&proxyman.SenderConfig{
+				StreamSettings: streamSettings,
+			},
+		),
+		ProxySettings: c.Proxy.OutboundSettings(c.Address, c.Port, c.Proxy.Account(c.UID, c.Cipher)),
+	}, nil
+}
+
+// Info returns the information about the server inbound the outbound connects to.
+func (c *OutboundConfig) Info() *InboundInfo {
+	info := &InboundInfo{
+		Proxy:             c.Proxy,
+		Transport:         c.Transport,
+		TLS:               c.TLS != nil,
+		Port:              c.Port,
+		Cipher:            c.Cipher,
+		TransportSettings: c.TransportSettings,
+	}
+
+	if c.TLS != nil {
+		info.ALPN = c.TLS.ALPN
+		info.ServerName = c.TLS.ServerName
+	}
+
+	return info
+}
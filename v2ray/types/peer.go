@@ -2,11 +2,20 @@ package types
 
 import (
 	"sync"
+
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/common/uuid"
 )
 
 // Peer represents an entity with an Email field.
 type Peer struct {
-	Email string
+	Email    string
+	Inbounds []string          // Inbounds is the list of tags of the inbounds serving the peer.
+	Level    uint32            // Level is the policy level the peer is assigned to.
+	Pinned   bool              // Pinned reports whether the peer chose its inbounds, instead of every inbound of its proxy.
+	Ports    map[string]uint16 // Ports maps the tags of single-user inbounds to the port of the inbound dedicated to the peer.
+	Proxy    Proxy             // Proxy is the proxy type the peer connects with.
+	UID      uuid.UUID         // UID is the UUID the peer account is derived from.
 }
 
 // Key returns the unique identifier (email) associated with the Peer.
@@ -14,6 +23,53 @@ func (p *Peer) Key() string {
 	return p.Email
 }
 
+// HasInbound checks whether the inbound with the given tag serves the Peer.
+func (p *Peer) HasInbound(tag string) bool {
+	for _, item := range p.Inbounds {
+		if item == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddInbound records that the inbound with the given tag serves the Peer.
+// The port is the port of the inbound dedicated to the peer, or zero if there is none.
+func (p *Peer) AddInbound(tag string, port uint16) {
+	if !p.HasInbound(tag) {
+		p.Inbounds = append(p.Inbounds, tag)
+	}
+	if port != 0 {
+		if p.Ports == nil {
+			p.Ports = make(map[string]uint16)
+		}
+
+		p.Ports[tag] = port
+	}
+}
+
+// RemoveInbound records that the inbound with the given tag no longer serves the Peer.
+func (p *Peer) RemoveInbound(tag string) {
+	for i, item := range p.Inbounds {
+		if item == tag {
+			p.Inbounds = append(p.Inbounds[:i], p.Inbounds[i+1:]...)
+			break
+		}
+	}
+
+	delete(p.Ports, tag)
+}
+
+// User returns the v2ray user of the Peer, with the account derived using the given cipher.
+func (p *Peer) User(cipher Cipher) *protocol.User {
+	return &protocol.User{
+		Level:   p.Level,
+		Email:   p.Email,
+		Account: p.Proxy.Account(p.UID, cipher),
+	}
+}
+
 // Peers is a thread-safe map-like structure that stores Peer objects.
 type Peers struct {
 	*sync.RWMutex
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"errors"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/app/policy"
+	featurespolicy "github.com/v2fly/v2ray-core/v5/features/policy"
+)
+
+// PolicyConfig represents the connection handling of the peers assigned to a policy level.
+// Zero durations and a zero buffer size fall back to the V2Ray defaults.
+// Limits on the number of connections or on the bandwidth of a level are not supported,
+// since the policies of V2Ray only set timeouts, buffer sizes and statistics.
+type PolicyConfig struct {
+	Level          uint32        // Level is the policy level the peers are assigned to.
+	Handshake      time.Duration // Handshake is the time allowed to complete the proxy handshake.
+	ConnectionIdle time.Duration // ConnectionIdle is the time a connection may stay idle before it is closed.
+	UplinkOnly     time.Duration // UplinkOnly is the time a connection is kept after the downlink is closed.
+	DownlinkOnly   time.Duration // DownlinkOnly is the time a connection is kept after the uplink is closed.
+	BufferSize     int32         // BufferSize is the buffer size per connection in bytes, or negative for unlimited.
+	DisableStats   bool          // DisableStats disables the traffic statistics of the peers.
+}
+
+// Validate checks whether the PolicyConfig is valid.
+func (c *PolicyConfig) Validate() error {
+	if c.Handshake < 0 || c.ConnectionIdle < 0 || c.UplinkOnly < 0 || c.DownlinkOnly < 0 {
+		return errors.New("policy timeouts cannot be negative")
+	}
+
+	return nil
+}
+
+// seconds converts the given duration to whole seconds, rounding up, or returns the fallback for zero.
+func seconds(d, fallback time.Duration) *policy.Second {
+	if d == 0 {
+		d = fallback
+	}
+
+	return &policy.Second{
+		Value: uint32((d + time.Second - 1) / time.Second),
+	}
+}
+
+// Policy builds the v2ray policy of the level.
+func (c *PolicyConfig) Policy() *policy.Policy {
+	defaults := featurespolicy.SessionDefault()
+
+	// All timeouts are set, since v2ray treats a missing timeout as zero.
+	p := &policy.Policy{
+		Timeout: &policy.Policy_Timeout{
+			Handshake:      seconds(c.Handshake, defaults.Timeouts.Handshake),
+			ConnectionIdle: seconds(c.ConnectionIdle, defaults.Timeouts.ConnectionIdle),
+			UplinkOnly:     seconds(c.UplinkOnly, defaults.Timeouts.UplinkOnly),
+			DownlinkOnly:   seconds(c.DownlinkOnly, defaults.Timeouts.DownlinkOnly),
+		},
+		Stats: &policy.Policy_Stats{
+			UserUplink:   !c.DisableStats,
+			UserDownlink: !c.DisableStats,
+		},
+	}
+
+	// Override the buffer size only if set, since v2ray treats a zero buffer size as no buffer.
+	if c.BufferSize != 0 {
+		p.Buffer = &policy.Policy_Buffer{
+			Connection: c.BufferSize,
+		}
+	}
+
+	return p
+}
@@ -1,10 +1,15 @@
 package types
 
 import (
+	"github.com/v2fly/v2ray-core/v5/common/net"
 	"github.com/v2fly/v2ray-core/v5/common/protocol"
 	"github.com/v2fly/v2ray-core/v5/common/serial"
 	"github.com/v2fly/v2ray-core/v5/common/uuid"
+	"github.com/v2fly/v2ray-core/v5/proxy/shadowsocks"
+	"github.com/v2fly/v2ray-core/v5/proxy/trojan"
 	"github.com/v2fly/v2ray-core/v5/proxy/vmess"
+	vmessinbound "github.com/v2fly/v2ray-core/v5/proxy/vmess/inbound"
+	vmessoutbound "github.com/v2fly/v2ray-core/v5/proxy/vmess/outbound"
 	"google.golang.org/protobuf/types/known/anypb"
 )
 
@@ -16,6 +21,10 @@ const (
 	ProxyUnspecified Proxy = 0x00 + iota
 	// ProxyVMess represents the VMess proxy type.
 	ProxyVMess
+	// ProxyTrojan represents the Trojan proxy type.
+	ProxyTrojan
+	// ProxyShadowsocks represents the Shadowsocks proxy type.
+	ProxyShadowsocks
 )
 
 // String returns a human-readable string representation of the Proxy type.
@@ -23,6 +32,10 @@ func (p Proxy) String() string {
 	switch p {
 	case ProxyVMess:
 		return "vmess"
+	case ProxyTrojan:
+		return "trojan"
+	case ProxyShadowsocks:
+		return "shadowsocks"
 	default:
 		return ""
 	}
@@ -33,8 +46,33 @@ func (p Proxy) Tag() string {
 	return p.String()
 }
 
+// IsValid checks whether the Proxy type is a known, specified proxy type.
+func (p Proxy) IsValid() bool {
+	return p.String() != ""
+}
+
+// RequiresTLS checks whether the Proxy type can only be served over TLS.
+// Trojan relies on TLS for both encryption and camouflage, so it is never served in plain text.
+func (p Proxy) RequiresTLS() bool {
+	return p == ProxyTrojan
+}
+
+// RequiresCipher checks whether the Proxy type requires a cipher to be configured.
+func (p Proxy) RequiresCipher() bool {
+	return p == ProxyShadowsocks
+}
+
+// IsMultiUser checks whether a single inbound of the Proxy type can serve multiple users
+// that are added and removed at runtime.
+// The Shadowsocks inbound of v2ray-core serves exactly one user, so every Shadowsocks peer
+// is served by a dedicated inbound instead.
+func (p Proxy) IsMultiUser() bool {
+	return p == ProxyVMess || p == ProxyTrojan
+}
+
 // Account generates an Any message containing the proxy account information.
-func (p Proxy) Account(uid uuid.UUID) *anypb.Any {
+// The cipher is only used by the proxy types that require one.
+func (p Proxy) Account(uid uuid.UUID, cipher Cipher) *anypb.Any {
 	switch p {
 	case ProxyVMess:
 		return serial.ToTypedMessage(
@@ -47,6 +85,95 @@ func (p Proxy) Account(uid uuid.UUID) *anypb.Any {
 				TestsEnabled: "",
 			},
 		)
+	case ProxyTrojan:
+		// The password of a Trojan account is derived from the peer UUID.
+		return serial.ToTypedMessage(
+			&trojan.Account{
+				Password: uid.String(),
+			},
+		)
+	case ProxyShadowsocks:
+		// The key of a Shadowsocks account is derived from the peer UUID.
+		return serial.ToTypedMessage(
+			&shadowsocks.Account{
+				Password:   uid.String(),
+				CipherType: cipher.ShadowsocksCipherType(),
+			},
+		)
+	default:
+		return nil
+	}
+}
+
+// InboundSettings generates an Any message containing the server-side proxy settings
+// with the given initial users.
+// Multi-user proxy types are usually created without any users, since users are added at runtime
+// with AddPeer. Other proxy types must be created with exactly one user.
+func (p Proxy) InboundSettings(users []*protocol.User) *anypb.Any {
+	switch p {
+	case ProxyVMess:
+		return serial.ToTypedMessage(
+			&vmessinbound.Config{
+				User: users,
+			},
+		)
+	case ProxyTrojan:
+		return serial.ToTypedMessage(
+			&trojan.ServerConfig{
+				Users: users,
+			},
+		)
+	case ProxyShadowsocks:
+		if len(users) != 1 {
+			return nil
+		}
+
+		return serial.ToTypedMessage(
+			&shadowsocks.ServerConfig{
+				User: users[0],
+				Network: []net.Network{
+					net.Network_TCP,
+					net.Network_UDP,
+				},
+			},
+		)
+	default:
+		return nil
+	}
+}
+
+// OutboundSettings generates an Any message containing the client-side proxy settings
+// for connecting to the server at the given address and port with the given account.
+func (p Proxy) OutboundSettings(addr string, port uint16, account *anypb.Any) *anypb.Any {
+	server := &protocol.ServerEndpoint{
+		Address: net.NewIPOrDomain(net.ParseAddress(addr)),
+		Port:    uint32(port),
+		User: []*protocol.User{
+			{
+				Account: account,
+			},
+		},
+	}
+
+	switch p {
+	case ProxyVMess:
+		return serial.ToTypedMessage(
+			&vmessoutbound.Config{
+				Receiver: []*protocol.ServerEndpoint{server},
+			},
+		)
+	case ProxyTrojan:
+		return serial.ToTypedMessage(
+			&trojan.ClientConfig{
+				Server: []*protocol.ServerEndpoint{server},
+			},
+		)
+	case ProxyShadowsocks:
+		return serial.ToTypedMessage(
+			&shadowsocks.ClientConfig{
+				Server: []*protocol.ServerEndpoint{server},
+			},
+		)
 	default:
 		return nil
 	}
@@ -57,6 +184,10 @@ func ProxyFromString(s string) Proxy {
 	switch s {
 	case "vmess":
 		return ProxyVMess
+	case "trojan":
+		return ProxyTrojan
+	case "shadowsocks":
+		return ProxyShadowsocks
 	default:
 		return ProxyUnspecified
 	}
@@ -0,0 +1,152 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/common/uuid"
+	"github.com/v2fly/v2ray-core/v5/proxy/shadowsocks"
+	"github.com/v2fly/v2ray-core/v5/proxy/trojan"
+	"github.com/v2fly/v2ray-core/v5/proxy/vmess"
+	vmessinbound "github.com/v2fly/v2ray-core/v5/proxy/vmess/inbound"
+	vmessoutbound "github.com/v2fly/v2ray-core/v5/proxy/vmess/outbound"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+func TestProxy_String(t *testing.T) {
+	tests := []struct {
+		proxy types.Proxy
+		want  string
+	}{
+		{types.ProxyUnspecified, ""},
+		{types.ProxyVMess, "vmess"},
+		{types.ProxyTrojan, "trojan"},
+		{types.ProxyShadowsocks, "shadowsocks"},
+		{types.Proxy(0xFF), ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.proxy.String(); got != tt.want {
+			t.Errorf("Proxy(%d).String() = %q, want %q", tt.proxy, got, tt.want)
+		}
+		if got := types.ProxyFromString(tt.want); tt.want != "" && got != tt.proxy {
+			t.Errorf("ProxyFromString(%q) = %d, want %d", tt.want, got, tt.proxy)
+		}
+		if got := tt.proxy.IsValid(); got != (tt.want != "") {
+			t.Errorf("Proxy(%d).IsValid() = %t, want %t", tt.proxy, got, tt.want != "")
+		}
+	}
+
+	if got := types.ProxyFromString("unknown"); got != types.ProxyUnspecified {
+		t.Errorf("ProxyFromString(%q) = %d, want %d", "unknown", got, types.ProxyUnspecified)
+	}
+}
+
+func TestProxy_Account(t *testing.T) {
+	uid := uuid.New()
+
+	t.Run("vmess", func(t *testing.T) {
+		var account vmess.Account
+		if err := types.ProxyVMess.Account(uid, types.CipherUnspecified).UnmarshalTo(&account); err != nil {
+			t.Fatalf("UnmarshalTo() error = %v", err)
+		}
+		if account.Id != uid.String() {
+			t.Errorf("Id = %q, want %q", account.Id, uid.String())
+		}
+		if account.SecuritySettings.GetType() != protocol.SecurityType_AUTO {
+			t.Errorf("SecuritySettings.Type = %s, want %s", account.SecuritySettings.GetType(), protocol.SecurityType_AUTO)
+		}
+	})
+
+	t.Run("trojan", func(t *testing.T) {
+		var account trojan.Account
+		if err := types.ProxyTrojan.Account(uid, types.CipherUnspecified).UnmarshalTo(&account); err != nil {
+			t.Fatalf("UnmarshalTo() error = %v", err)
+		}
+		if account.Password != uid.String() {
+			t.Errorf("Password = %q, want %q", account.Password, uid.String())
+		}
+	})
+
+	t.Run("shadowsocks", func(t *testing.T) {
+		var account shadowsocks.Account
+		if err := types.ProxyShadowsocks.Account(uid, types.CipherAES256GCM).UnmarshalTo(&account); err != nil {
+			t.Fatalf("UnmarshalTo() error = %v", err)
+		}
+		if account.Password != uid.String() {
+			t.Errorf("Password = %q, want %q", account.Password, uid.String())
+		}
+		if account.CipherType != shadowsocks.CipherType_AES_256_GCM {
+			t.Errorf("CipherType = %s, want %s", account.CipherType, shadowsocks.CipherType_AES_256_GCM)
+		}
+	})
+
+	t.Run("unspecified", func(t *testing.T) {
+		if got := types.ProxyUnspecified.Account(uid, types.CipherUnspecified); got != nil {
+			t.Errorf("Account() = %v, want nil", got)
+		}
+	})
+}
+
+func TestProxy_InboundSettings(t *testing.T) {
+	users := []*protocol.User{
+		{Email: "peer", Account: types.ProxyVMess.Account(uuid.New(), types.CipherUnspecified)},
+	}
+
+	var vmessConfig vmessinbound.Config
+	if err := types.ProxyVMess.InboundSettings(users).UnmarshalTo(&vmessConfig); err != nil {
+		t.Fatalf("UnmarshalTo() error = %v", err)
+	}
+	if len(vmessConfig.User) != 1 || vmessConfig.User[0].Email != "peer" {
+		t.Errorf("User = %v, want the given user", vmessConfig.User)
+	}
+
+	var trojanConfig trojan.ServerConfig
+	if err := types.ProxyTrojan.InboundSettings(nil).UnmarshalTo(&trojanConfig); err != nil {
+		t.Fatalf("UnmarshalTo() error = %v", err)
+	}
+	if len(trojanConfig.Users) != 0 {
+		t.Errorf("Users = %v, want none", trojanConfig.Users)
+	}
+
+	var ssConfig shadowsocks.ServerConfig
+	if err := types.ProxyShadowsocks.InboundSettings(users).UnmarshalTo(&ssConfig); err != nil {
+		t.Fatalf("UnmarshalTo() error = %v", err)
+	}
+	if ssConfig.User.GetEmail() != "peer" || len(ssConfig.Network) != 2 {
+		t.Errorf("ServerConfig = %v, want the given user over tcp and udp", &ssConfig)
+	}
+
+	// A Shadowsocks inbound serves exactly one user.
+	if got := types.ProxyShadowsocks.InboundSettings(nil); got != nil {
+		t.Errorf("InboundSettings(nil) = %v, want nil", got)
+	}
+}
+
+func TestProxy_OutboundSettings(t *testing.T) {
+	account := types.ProxyVMess.Account(uuid.New(), types.CipherUnspecified)
+
+	var config vmessoutbound.Config
+	if err := types.ProxyVMess.OutboundSettings("203.0.113.1", 443, account).UnmarshalTo(&config); err != nil {
+		t.Fatalf("UnmarshalTo() error = %v", err)
+	}
+	if len(config.Receiver) != 1 {
+		t.Fatalf("Receiver = %v, want one server", config.Receiver)
+	}
+
+	server := config.Receiver[0]
+	if got := server.Address.AsAddress().String(); got != "203.0.113.1" {
+		t.Errorf("Address = %q, want %q", got, "203.0.113.1")
+	}
+	if server.Port != 443 {
+		t.Errorf("Port = %d, want %d", server.Port, 443)
+	}
+	if len(server.User) != 1 || server.User[0].Account.GetTypeUrl() != account.GetTypeUrl() {
+		t.Errorf("User = %v, want the given account", server.User)
+	}
+
+	if got := types.ProxyUnspecified.OutboundSettings("203.0.113.1", 443, account); got != nil {
+		t.Errorf("OutboundSettings() = %v, want nil", got)
+	}
+}
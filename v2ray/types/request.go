@@ -0,0 +1,116 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/v2fly/v2ray-core/v5/common/uuid"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+const (
+	// peerRequestKeyLen represents the length of the proxy and the UUID, which identify the peer of a request.
+	peerRequestKeyLen = 1 + 16
+
+	// peerRequestLen represents the length of a request without any ports.
+	peerRequestLen = peerRequestKeyLen + 1
+)
+
+// PeerRequest represents the V2Ray payload of a request to add, check or remove a peer.
+//
+// It is laid out as follows, with multi-byte integers in big-endian byte order:
+//
+//	proxy (1 byte) | uuid (16 bytes) | count (1 byte) | count ports (2 bytes each)
+//
+// Only the proxy and the UUID are required; the ports may be omitted, and decoders skip the bytes
+// appended by later revisions. The policy level of the peer is chosen by the node, and is not part
+// of the request. None of the served proxies implements flow control, so the request carries no flow.
+type PeerRequest struct {
+	Proxy Proxy     // Proxy is the proxy type the peer connects with.
+	UID   uuid.UUID // UID is the UUID the peer account is derived from.
+	Ports []uint16  // Ports is the list of ports of the inbounds chosen to serve the peer, or empty for all of them.
+}
+
+// Email returns the email identifying the peer in the V2Ray server, derived from the proxy and the UUID.
+func (r *PeerRequest) Email() string {
+	buf := append([]byte{byte(r.Proxy)}, r.UID.Bytes()...)
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// MarshalBinary encodes the PeerRequest in the binary format.
+func (r *PeerRequest) MarshalBinary() ([]byte, error) {
+	if len(r.Ports) > 255 {
+		return nil, fmt.Errorf("too many ports; expected at most 255, got %d", len(r.Ports))
+	}
+
+	buf := make([]byte, 0, peerRequestLen+2*len(r.Ports))
+	buf = append(buf, byte(r.Proxy))
+	buf = append(buf, r.UID.Bytes()...)
+	buf = append(buf, byte(len(r.Ports)))
+
+	for _, port := range r.Ports {
+		buf = binary.BigEndian.AppendUint16(buf, port)
+	}
+
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the PeerRequest from the binary format.
+func (r *PeerRequest) UnmarshalBinary(buf []byte) error {
+	if len(buf) < peerRequestKeyLen {
+		return fmt.Errorf("%w: invalid peer request length; expected at least %d, got %d", sentinelsdk.ErrInvalidPayload, peerRequestKeyLen, len(buf))
+	}
+
+	// Parse the UUID of the peer.
+	uid, err := uuid.ParseBytes(buf[1:peerRequestKeyLen])
+	if err != nil {
+		return fmt.Errorf("%w: %s", sentinelsdk.ErrInvalidPayload, err)
+	}
+
+	req := PeerRequest{
+		Proxy: Proxy(buf[0]),
+		UID:   uid,
+	}
+
+	// Parse the optional fields, if present.
+	if len(buf) >= peerRequestLen {
+		count := int(buf[peerRequestLen-1])
+		if len(buf) < peerRequestLen+2*count {
+			return fmt.Errorf("%w: invalid peer request length; expected at least %d, got %d", sentinelsdk.ErrInvalidPayload, peerRequestLen+2*count, len(buf))
+		}
+
+		for i := 0; i < count; i++ {
+			req.Ports = append(req.Ports, binary.BigEndian.Uint16(buf[peerRequestLen+2*i:]))
+		}
+	}
+
+	*r = req
+	return nil
+}
+
+// PeerResponse represents the V2Ray payload of the response to a request adding a peer.
+// It is encoded in the binary format of Info.
+type PeerResponse struct {
+	// Inbounds is the list of inbounds serving the peer. The port of an inbound dedicated to the peer
+	// is the port assigned to it, and its number of ports is 0.
+	Inbounds []*InboundInfo
+}
+
+// MarshalBinary encodes the PeerResponse in the binary format.
+func (r *PeerResponse) MarshalBinary() ([]byte, error) {
+	info := &Info{Inbounds: r.Inbounds}
+	return info.MarshalBinary()
+}
+
+// UnmarshalBinary decodes the PeerResponse from the binary format.
+func (r *PeerResponse) UnmarshalBinary(buf []byte) error {
+	var info Info
+	if err := info.UnmarshalBinary(buf); err != nil {
+		return err
+	}
+
+	r.Inbounds = info.Inbounds
+	return nil
+}
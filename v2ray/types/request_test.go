@@ -0,0 +1,167 @@
+package types_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/common/uuid"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+func TestPeerRequest_MarshalBinary(t *testing.T) {
+	uid := uuid.New()
+
+	tests := []struct {
+		name string
+		req  *types.PeerRequest
+		want []byte
+	}{
+		{
+			name: "proxy and uuid",
+			req:  &types.PeerRequest{Proxy: types.ProxyVMess, UID: uid},
+			want: append(append([]byte{byte(types.ProxyVMess)}, uid.Bytes()...), 0x00),
+		},
+		{
+			name: "ports",
+			req:  &types.PeerRequest{Proxy: types.ProxyShadowsocks, UID: uid, Ports: []uint16{8388, 8389}},
+			want: append(append([]byte{byte(types.ProxyShadowsocks)}, uid.Bytes()...), 0x02, 0x20, 0xC4, 0x20, 0xC5),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := tt.req.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			if !bytes.Equal(buf, tt.want) {
+				t.Fatalf("MarshalBinary() = %x, want %x", buf, tt.want)
+			}
+
+			var got types.PeerRequest
+			if err := got.UnmarshalBinary(buf); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if !reflect.DeepEqual(&got, tt.req) {
+				t.Fatalf("UnmarshalBinary() = %+v, want %+v", got, tt.req)
+			}
+			if got.Email() != tt.req.Email() {
+				t.Fatalf("Email() = %q, want %q", got.Email(), tt.req.Email())
+			}
+		})
+	}
+}
+
+func TestPeerRequest_MarshalBinary_Limits(t *testing.T) {
+	if _, err := (&types.PeerRequest{Ports: make([]uint16, 256)}).MarshalBinary(); err == nil {
+		t.Error("MarshalBinary() error = nil, want an error for 256 ports")
+	}
+}
+
+func TestPeerRequest_UnmarshalBinary(t *testing.T) {
+	uid := uuid.New()
+	key := append([]byte{byte(types.ProxyVMess)}, uid.Bytes()...)
+
+	// join concatenates the given byte slices after the proxy and the UUID.
+	join := func(items ...[]byte) []byte {
+		buf := append([]byte{}, key...)
+		for _, item := range items {
+			buf = append(buf, item...)
+		}
+
+		return buf
+	}
+
+	tests := []struct {
+		name    string
+		buf     []byte
+		want    *types.PeerRequest
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			buf:     nil,
+			wantErr: true,
+		},
+		{
+			name:    "truncated uuid",
+			buf:     key[:10],
+			wantErr: true,
+		},
+		{
+			name: "proxy and uuid only",
+			buf:  join(),
+			want: &types.PeerRequest{Proxy: types.ProxyVMess, UID: uid},
+		},
+		{
+			name: "no ports",
+			buf:  join([]byte{0x00}),
+			want: &types.PeerRequest{Proxy: types.ProxyVMess, UID: uid},
+		},
+		{
+			name: "ports",
+			buf:  join([]byte{0x02, 0x01, 0xBB, 0x20, 0xFB}),
+			want: &types.PeerRequest{Proxy: types.ProxyVMess, UID: uid, Ports: []uint16{443, 8443}},
+		},
+		{
+			name:    "truncated ports",
+			buf:     join([]byte{0x02, 0x01, 0xBB, 0x20}),
+			wantErr: true,
+		},
+		{
+			name: "trailing bytes of a later revision",
+			buf:  join([]byte{0x01, 0x01, 0xBB, 0x03}, []byte("abc")),
+			want: &types.PeerRequest{Proxy: types.ProxyVMess, UID: uid, Ports: []uint16{443}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got types.PeerRequest
+
+			err := got.UnmarshalBinary(tt.buf)
+			if tt.wantErr {
+				if !errors.Is(err, sentinelsdk.ErrInvalidPayload) {
+					t.Fatalf("UnmarshalBinary() error = %v, want %v", err, sentinelsdk.ErrInvalidPayload)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if !reflect.DeepEqual(&got, tt.want) {
+				t.Fatalf("UnmarshalBinary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeerResponse_MarshalBinary(t *testing.T) {
+	res := &types.PeerResponse{
+		Inbounds: []*types.InboundInfo{
+			{Proxy: types.ProxyShadowsocks, Transport: types.TransportTCP, Port: 8389, Cipher: types.CipherAES128GCM},
+			{Proxy: types.ProxyTrojan, Transport: types.TransportTCP, TLS: true, Port: 443},
+		},
+	}
+
+	buf, err := res.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	var got types.PeerResponse
+	if err := got.UnmarshalBinary(buf); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	if !reflect.DeepEqual(&got, res) {
+		t.Fatalf("UnmarshalBinary() = %+v, want %+v", got.Inbounds, res.Inbounds)
+	}
+
+	if err := got.UnmarshalBinary(buf[:len(buf)-1]); !errors.Is(err, sentinelsdk.ErrInvalidPayload) {
+		t.Fatalf("UnmarshalBinary() error = %v, want %v", err, sentinelsdk.ErrInvalidPayload)
+	}
+}
@@ -0,0 +1,304 @@
+package types
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+	v2net "github.com/v2fly/v2ray-core/v5/common/net"
+)
+
+// PrivateCIDRs is the list of private, loopback, link-local and otherwise non-public networks
+// blocked by RoutingConfig.BlockPrivate.
+var PrivateCIDRs = []string{
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"224.0.0.0/4",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+}
+
+// LoopbackCIDRs is the list of networks reaching the node itself, such as the local V2Ray API.
+// They are always blocked for peers by LoopbackRules, whatever the RoutingConfig.
+var LoopbackCIDRs = []string{
+	"0.0.0.0/8",
+	"127.0.0.0/8",
+	"::/128",
+	"::1/128",
+}
+
+// LoopbackDomains is the list of domains reaching the node itself, blocked along with LoopbackCIDRs.
+var LoopbackDomains = []string{
+	"full:localhost",
+	"localhost.localdomain",
+}
+
+// RoutingConfig represents the rules restricting the destinations peers can reach through the server.
+// Blocked traffic is routed to an outbound that drops it.
+type RoutingConfig struct {
+	BlockPrivate    bool     // BlockPrivate blocks the destinations in PrivateCIDRs, including the node itself.
+	BlockPorts      []uint16 // BlockPorts is the list of blocked destination ports, such as 25 for SMTP.
+	BlockBitTorrent bool     // BlockBitTorrent blocks BitTorrent traffic; it requires sniffing on the inbounds.
+
+	// BlockDomains is the list of blocked domains. An entry matches the domain and its subdomains,
+	// unless it has one of the prefixes "full:", "keyword:" or "regexp:".
+	BlockDomains []string
+	// BlockDomainsFile is the path to a file with additional blocked domains, one per line.
+	BlockDomainsFile string
+	// BlockIPs is the list of blocked IP addresses and networks in the CIDR notation.
+	BlockIPs []string
+	// BlockIPsFile is the path to a file with additional blocked IP addresses and networks, one per line.
+	BlockIPsFile string
+}
+
+// readLines reads the non-empty lines of the given file, skipping comments starting with '#'.
+func readLines(name string) ([]string, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err = file.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	var items []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		items = append(items, line)
+	}
+
+	return items, scanner.Err()
+}
+
+// parseCIDR converts an IP address or a network in the CIDR notation to a v2ray CIDR.
+func parseCIDR(s string) (*routercommon.CIDR, error) {
+	if !strings.Contains(s, "/") {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ip address %s", s)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return &routercommon.CIDR{Ip: ip4, Prefix: 32}, nil
+		}
+
+		return &routercommon.CIDR{Ip: ip, Prefix: 128}, nil
+	}
+
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, _ := network.Mask.Size()
+	return &routercommon.CIDR{Ip: network.IP, Prefix: uint32(prefix)}, nil
+}
+
+// parseDomain converts a domain entry to a v2ray domain matcher.
+func parseDomain(s string) (*routercommon.Domain, error) {
+	var (
+		kind  = routercommon.Domain_RootDomain
+		value = s
+	)
+
+	switch {
+	case strings.HasPrefix(s, "full:"):
+		kind, value = routercommon.Domain_Full, strings.TrimPrefix(s, "full:")
+	case strings.HasPrefix(s, "keyword:"):
+		kind, value = routercommon.Domain_Plain, strings.TrimPrefix(s, "keyword:")
+	case strings.HasPrefix(s, "regexp:"):
+		kind, value = routercommon.Domain_Regex, strings.TrimPrefix(s, "regexp:")
+	case strings.HasPrefix(s, "domain:"):
+		value = strings.TrimPrefix(s, "domain:")
+	}
+
+	if value == "" {
+		return nil, fmt.Errorf("invalid domain %s", s)
+	}
+
+	return &routercommon.Domain{Type: kind, Value: value}, nil
+}
+
+// cidrs returns the blocked IP addresses and networks, including the ones read from the file.
+func (c *RoutingConfig) cidrs() ([]*routercommon.CIDR, error) {
+	lines, err := readLines(c.BlockIPsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*routercommon.CIDR
+	for _, s := range append(append([]string(nil), c.BlockIPs...), lines...) {
+		cidr, err := parseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, cidr)
+	}
+
+	return items, nil
+}
+
+// domains returns the blocked domains, including the ones read from the file.
+func (c *RoutingConfig) domains() ([]*routercommon.Domain, error) {
+	lines, err := readLines(c.BlockDomainsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*routercommon.Domain
+	for _, s := range append(append([]string(nil), c.BlockDomains...), lines...) {
+		domain, err := parseDomain(s)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, domain)
+	}
+
+	return items, nil
+}
+
+// Validate checks whether the RoutingConfig is valid, including the entries of the blocklist files.
+func (c *RoutingConfig) Validate() error {
+	for _, port := range c.BlockPorts {
+		if port == 0 {
+			return fmt.Errorf("invalid blocked port %d", port)
+		}
+	}
+
+	_, err := c.Rules("")
+	return err
+}
+
+// LoopbackRules builds the v2ray routing rules sending the traffic to the node itself to the outbound
+// with the given tag. The router must resolve domains not matched by the rules, with the strategy
+// router.DomainStrategy_IpIfNonMatch, so a domain resolving to a loopback address is blocked too.
+func LoopbackRules(tag string) ([]*router.RoutingRule, error) {
+	target := &router.RoutingRule_Tag{Tag: tag}
+
+	var cidrs []*routercommon.CIDR
+	for _, s := range LoopbackCIDRs {
+		cidr, err := parseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+
+		cidrs = append(cidrs, cidr)
+	}
+
+	var domains []*routercommon.Domain
+	for _, s := range LoopbackDomains {
+		domain, err := parseDomain(s)
+		if err != nil {
+			return nil, err
+		}
+
+		domains = append(domains, domain)
+	}
+
+	return []*router.RoutingRule{
+		{
+			TargetTag: target,
+			Domain:    domains,
+		},
+		{
+			TargetTag: target,
+			Geoip:     []*routercommon.GeoIP{{Cidr: cidrs}},
+		},
+	}, nil
+}
+
+// Rules builds the v2ray routing rules sending the blocked traffic to the outbound with the given tag.
+func (c *RoutingConfig) Rules(tag string) ([]*router.RoutingRule, error) {
+	var (
+		rules  []*router.RoutingRule
+		target = &router.RoutingRule_Tag{Tag: tag}
+	)
+
+	// Block the private networks.
+	if c.BlockPrivate {
+		var cidrs []*routercommon.CIDR
+		for _, s := range PrivateCIDRs {
+			cidr, err := parseCIDR(s)
+			if err != nil {
+				return nil, err
+			}
+
+			cidrs = append(cidrs, cidr)
+		}
+
+		rules = append(rules, &router.RoutingRule{
+			TargetTag: target,
+			Geoip:     []*routercommon.GeoIP{{Cidr: cidrs}},
+		})
+	}
+
+	// Block the destination ports.
+	if len(c.BlockPorts) > 0 {
+		ports := &v2net.PortList{}
+		for _, port := range c.BlockPorts {
+			ports.Range = append(ports.Range, v2net.SinglePortRange(v2net.Port(port)))
+		}
+
+		rules = append(rules, &router.RoutingRule{
+			TargetTag: target,
+			PortList:  ports,
+		})
+	}
+
+	// Block the BitTorrent traffic detected by sniffing.
+	if c.BlockBitTorrent {
+		rules = append(rules, &router.RoutingRule{
+			TargetTag: target,
+			Protocol:  []string{"bittorrent"},
+		})
+	}
+
+	// Block the domains.
+	domains, err := c.domains()
+	if err != nil {
+		return nil, err
+	}
+	if len(domains) > 0 {
+		rules = append(rules, &router.RoutingRule{
+			TargetTag: target,
+			Domain:    domains,
+		})
+	}
+
+	// Block the IP addresses and networks.
+	cidrs, err := c.cidrs()
+	if err != nil {
+		return nil, err
+	}
+	if len(cidrs) > 0 {
+		rules = append(rules, &router.RoutingRule{
+			TargetTag: target,
+			Geoip:     []*routercommon.GeoIP{{Cidr: cidrs}},
+		})
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,176 @@
+package types_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+func TestLoopbackRules(t *testing.T) {
+	rules, err := types.LoopbackRules("block")
+	if err != nil {
+		t.Fatalf("LoopbackRules() error = %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("LoopbackRules() = %d rules, want 2", len(rules))
+	}
+
+	for _, rule := range rules {
+		if rule.GetTag() != "block" {
+			t.Errorf("rule tag = %q, want %q", rule.GetTag(), "block")
+		}
+	}
+
+	if got := len(rules[0].Domain); got != len(types.LoopbackDomains) {
+		t.Errorf("domains = %d, want %d", got, len(types.LoopbackDomains))
+	}
+	if rules[0].Domain[0].Type != routercommon.Domain_Full || rules[0].Domain[0].Value != "localhost" {
+		t.Errorf("domain = %v, want full:localhost", rules[0].Domain[0])
+	}
+
+	cidrs := rules[1].Geoip[0].Cidr
+	if len(cidrs) != len(types.LoopbackCIDRs) {
+		t.Fatalf("cidrs = %d, want %d", len(cidrs), len(types.LoopbackCIDRs))
+	}
+	if !containsCIDR(cidrs, []byte{127, 0, 0, 0}, 8) {
+		t.Errorf("cidrs = %v, want 127.0.0.0/8", cidrs)
+	}
+	if !containsCIDR(cidrs, []byte{0, 0, 0, 0}, 8) {
+		t.Errorf("cidrs = %v, want 0.0.0.0/8", cidrs)
+	}
+}
+
+func TestRoutingConfig_Rules(t *testing.T) {
+	dir := t.TempDir()
+
+	domainsFile := filepath.Join(dir, "domains.txt")
+	if err := os.WriteFile(domainsFile, []byte("# comment\nexample.org\n\nregexp:^ads\\.\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ipsFile := filepath.Join(dir, "ips.txt")
+	if err := os.WriteFile(ipsFile, []byte("198.51.100.0/24\n2001:db8::1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  *types.RoutingConfig
+		check   func(t *testing.T, rules []*router.RoutingRule)
+		wantErr bool
+	}{
+		{
+			name:   "empty",
+			config: &types.RoutingConfig{},
+			check: func(t *testing.T, rules []*router.RoutingRule) {
+				if len(rules) != 0 {
+					t.Errorf("rules = %v, want none", rules)
+				}
+			},
+		},
+		{
+			name:   "private networks",
+			config: &types.RoutingConfig{BlockPrivate: true},
+			check: func(t *testing.T, rules []*router.RoutingRule) {
+				if len(rules) != 1 || len(rules[0].Geoip[0].Cidr) != len(types.PrivateCIDRs) {
+					t.Errorf("rules = %v, want one rule with the private networks", rules)
+				}
+			},
+		},
+		{
+			name:   "ports and bittorrent",
+			config: &types.RoutingConfig{BlockPorts: []uint16{25, 465}, BlockBitTorrent: true},
+			check: func(t *testing.T, rules []*router.RoutingRule) {
+				if len(rules) != 2 {
+					t.Fatalf("rules = %v, want 2 rules", rules)
+				}
+				if got := rules[0].PortList.GetRange(); len(got) != 2 || got[0].From != 25 || got[1].To != 465 {
+					t.Errorf("ports = %v, want 25 and 465", got)
+				}
+				if got := rules[1].Protocol; len(got) != 1 || got[0] != "bittorrent" {
+					t.Errorf("protocol = %v, want bittorrent", got)
+				}
+			},
+		},
+		{
+			name: "domains and ips with files",
+			config: &types.RoutingConfig{
+				BlockDomains:     []string{"full:tracker.example.com", "keyword:casino"},
+				BlockDomainsFile: domainsFile,
+				BlockIPs:         []string{"203.0.113.7"},
+				BlockIPsFile:     ipsFile,
+			},
+			check: func(t *testing.T, rules []*router.RoutingRule) {
+				if len(rules) != 2 {
+					t.Fatalf("rules = %v, want 2 rules", rules)
+				}
+
+				domains := rules[0].Domain
+				if len(domains) != 4 {
+					t.Fatalf("domains = %v, want 4", domains)
+				}
+				if domains[0].Type != routercommon.Domain_Full || domains[1].Type != routercommon.Domain_Plain ||
+					domains[2].Type != routercommon.Domain_RootDomain || domains[3].Type != routercommon.Domain_Regex {
+					t.Errorf("domains = %v, want full, keyword, root domain and regexp", domains)
+				}
+
+				cidrs := rules[1].Geoip[0].Cidr
+				if len(cidrs) != 3 {
+					t.Fatalf("cidrs = %v, want 3", cidrs)
+				}
+				if !containsCIDR(cidrs, []byte{203, 0, 113, 7}, 32) || !containsCIDR(cidrs, []byte{198, 51, 100, 0}, 24) {
+					t.Errorf("cidrs = %v, want the given addresses", cidrs)
+				}
+				if cidrs[2].Prefix != 128 {
+					t.Errorf("prefix = %d, want 128 for an ipv6 address", cidrs[2].Prefix)
+				}
+			},
+		},
+		{
+			name:    "invalid ip",
+			config:  &types.RoutingConfig{BlockIPs: []string{"not-an-ip"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid domain",
+			config:  &types.RoutingConfig{BlockDomains: []string{"full:"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			config:  &types.RoutingConfig{BlockIPsFile: filepath.Join(dir, "missing.txt")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := tt.config.Rules("block")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Rules() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if (tt.config.Validate() != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %t", tt.config.Validate(), tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, rules)
+			}
+		})
+	}
+}
+
+// containsCIDR checks whether the list of CIDRs contains the given network.
+func containsCIDR(cidrs []*routercommon.CIDR, ip []byte, prefix uint32) bool {
+	for _, cidr := range cidrs {
+		if string(cidr.Ip) == string(ip) && cidr.Prefix == prefix {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,157 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common/serial"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/tls"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// TLSConfig represents the TLS settings of an inbound or outbound.
+type TLSConfig struct {
+	ALPN          []string // ALPN is the list of application protocols negotiated with the peer, in order of preference.
+	AllowInsecure bool     // AllowInsecure skips the verification of the server certificate (client only, for testing).
+	CertFile      string   // CertFile is the path to the PEM encoded certificate (server only).
+	KeyFile       string   // KeyFile is the path to the PEM encoded private key (server only).
+	ServerName    string   // ServerName is the name used for SNI and certificate verification.
+}
+
+// Validate checks whether the TLSConfig is valid for a server.
+func (c *TLSConfig) Validate() error {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return errors.New("tls certificate and key files are required")
+	}
+
+	return nil
+}
+
+// Files returns the paths of the certificate and key files.
+func (c *TLSConfig) Files() []string {
+	return []string{c.CertFile, c.KeyFile}
+}
+
+// ServerSecuritySettings generates an Any message containing the server-side TLS settings.
+// The certificate and the key are read from the configured files, so calling it again
+// picks up certificates that changed on disk.
+func (c *TLSConfig) ServerSecuritySettings() (*anypb.Any, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Read the PEM encoded certificate.
+	cert, err := os.ReadFile(c.CertFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the PEM encoded private key.
+	key, err := os.ReadFile(c.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return serial.ToTypedMessage(
+		&tls.Config{
+			Certificate: []*tls.Certificate{
+				{
+					Certificate: cert,
+					Key:         key,
+					Usage:       tls.Certificate_ENCIPHERMENT,
+				},
+			},
+			ServerName:   c.ServerName,
+			NextProtocol: c.ALPN,
+		},
+	), nil
+}
+
+// ClientSecuritySettings generates an Any message containing the client-side TLS settings.
+func (c *TLSConfig) ClientSecuritySettings() *anypb.Any {
+	return serial.ToTypedMessage(
+		&tls.Config{
+			AllowInsecure: c.AllowInsecure,
+			ServerName:    c.ServerName,
+			NextProtocol:  c.ALPN,
+		},
+	)
+}
+
+// SecurityType returns the message type name used to select the TLS security settings of a stream.
+func (c *TLSConfig) SecurityType() string {
+	return serial.GetMessageType(&tls.Config{})
+}
+
+// GenerateSelfSignedCertificate generates a self-signed ECDSA P-256 certificate valid for the given hosts
+// (DNS names or IP addresses) and duration, and writes the PEM encoded certificate and key to the configured files.
+// Self-signed certificates are intended for testing; clients must set AllowInsecure to connect.
+func (c *TLSConfig) GenerateSelfSignedCertificate(hosts []string, validity time.Duration) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	// Generate the private key.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	// Generate a random serial number.
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+
+	// Prepare the certificate template.
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: c.ServerName},
+		NotBefore:             now,
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+
+	// Add each host to the certificate as an IP address or a DNS name.
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	// Sign the certificate with its own key.
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+
+	// Encode the private key in the PKCS #8 format.
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	// Write the PEM encoded certificate.
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})
+	if err := os.WriteFile(c.CertFile, certPEM, 0644); err != nil {
+		return err
+	}
+
+	// Write the PEM encoded private key.
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})
+	return os.WriteFile(c.KeyFile, keyPEM, 0600)
+}
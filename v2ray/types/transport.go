@@ -1,5 +1,23 @@
 package types
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/common/serial"
+	"github.com/v2fly/v2ray-core/v5/transport/internet"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/domainsocket"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/grpc"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/http"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/kcp"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/quic"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/tcp"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/websocket"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
 // Transport represents different transport protocols supported by the system.
 type Transport byte
 
@@ -71,3 +89,182 @@ func NewTransportFromString(v string) Transport {
 		return TransportUnspecified
 	}
 }
+
+// ProtocolName returns the name v2ray registers the transport protocol under.
+func (t Transport) ProtocolName() string {
+	switch t {
+	case TransportGUN, TransportGRPC:
+		return "gun"
+	default:
+		return t.String()
+	}
+}
+
+// IsValid checks whether the Transport type is a known, specified transport protocol.
+func (t Transport) IsValid() bool {
+	return t.String() != ""
+}
+
+// RequiresTLS checks whether the Transport type can only be served over TLS.
+// The HTTP transport of v2ray is HTTP/2, which it only negotiates over TLS. QUIC carries its own
+// encryption and does not need the TLS settings.
+func (t Transport) RequiresTLS() bool {
+	return t == TransportHTTP
+}
+
+// TransportSettings represents the settings of a transport protocol.
+// Each transport protocol uses only the fields relevant to it; the other fields are ignored.
+type TransportSettings struct {
+	Headers      map[string]string // Headers are the HTTP headers sent with the handshake (websocket).
+	Host         []string          // Host is the list of HTTP hosts (http) or the authority (gun, grpc).
+	Key          string            // Key is the encryption key (quic).
+	PacketHeader PacketHeader      // PacketHeader is the packet header obfuscation (mkcp, quic).
+	Path         string            // Path is the HTTP path (websocket, http) or the socket path (domainsocket).
+	Security     Cipher            // Security is the packet encryption cipher (quic).
+	Seed         string            // Seed is the encryption seed (mkcp).
+	ServiceName  string            // ServiceName is the gRPC service name (gun, grpc).
+}
+
+// Validate checks whether the TransportSettings are valid for the given transport protocol.
+func (s *TransportSettings) Validate(t Transport) error {
+	switch t {
+	case TransportMKCP:
+		if !s.PacketHeader.IsValid() {
+			return fmt.Errorf("invalid packet header %d", s.PacketHeader)
+		}
+	case TransportDomainSocket:
+		if s.Path == "" {
+			return errors.New("path cannot be empty for transport domainsocket")
+		}
+	case TransportQUIC:
+		if !s.PacketHeader.IsValid() {
+			return fmt.Errorf("invalid packet header %d", s.PacketHeader)
+		}
+		if s.Security != CipherUnspecified && s.Security != CipherAES128GCM && s.Security != CipherChaCha20Poly1305 {
+			return fmt.Errorf("security %s is not supported for transport quic", s.Security)
+		}
+		if s.Security != CipherUnspecified && s.Key == "" {
+			return errors.New("key cannot be empty when security is set for transport quic")
+		}
+	}
+
+	return nil
+}
+
+// settings generates an Any message containing the settings of the given transport protocol.
+func (s *TransportSettings) settings(t Transport) *anypb.Any {
+	switch t {
+	case TransportTCP:
+		return serial.ToTypedMessage(
+			&tcp.Config{},
+		)
+	case TransportMKCP:
+		var seed *kcp.EncryptionSeed
+		if s.Seed != "" {
+			seed = &kcp.EncryptionSeed{
+				Seed: s.Seed,
+			}
+		}
+
+		return serial.ToTypedMessage(
+			&kcp.Config{
+				HeaderConfig: s.PacketHeader.Settings(),
+				Seed:         seed,
+			},
+		)
+	case TransportWebSocket:
+		// Sort the header keys, so the generated settings are deterministic.
+		keys := make([]string, 0, len(s.Headers))
+		for key := range s.Headers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		headers := make([]*websocket.Header, 0, len(keys))
+		for _, key := range keys {
+			headers = append(
+				headers,
+				&websocket.Header{
+					Key:   key,
+					Value: s.Headers[key],
+				},
+			)
+		}
+
+		return serial.ToTypedMessage(
+			&websocket.Config{
+				Path:   s.Path,
+				Header: headers,
+			},
+		)
+	case TransportHTTP:
+		return serial.ToTypedMessage(
+			&http.Config{
+				Host: s.Host,
+				Path: s.Path,
+			},
+		)
+	case TransportDomainSocket:
+		return serial.ToTypedMessage(
+			&domainsocket.Config{
+				Path: s.Path,
+			},
+		)
+	case TransportQUIC:
+		security := protocol.SecurityType_NONE
+		switch s.Security {
+		case CipherAES128GCM:
+			security = protocol.SecurityType_AES128_GCM
+		case CipherChaCha20Poly1305:
+			security = protocol.SecurityType_CHACHA20_POLY1305
+		}
+
+		return serial.ToTypedMessage(
+			&quic.Config{
+				Key: s.Key,
+				Security: &protocol.SecurityConfig{
+					Type: security,
+				},
+				Header: s.PacketHeader.Settings(),
+			},
+		)
+	case TransportGUN, TransportGRPC:
+		var host string
+		if len(s.Host) > 0 {
+			host = s.Host[0]
+		}
+
+		return serial.ToTypedMessage(
+			&grpc.Config{
+				Host:        host,
+				ServiceName: s.ServiceName,
+			},
+		)
+	default:
+		return nil
+	}
+}
+
+// StreamSettings builds the v2ray stream settings of the transport protocol with the given settings.
+// Nil settings are treated as empty settings.
+func (t Transport) StreamSettings(s *TransportSettings) (*internet.StreamConfig, error) {
+	if !t.IsValid() {
+		return nil, fmt.Errorf("invalid transport %d", t)
+	}
+	if s == nil {
+		s = &TransportSettings{}
+	}
+	if err := s.Validate(t); err != nil {
+		return nil, err
+	}
+
+	return &internet.StreamConfig{
+		ProtocolName: t.ProtocolName(),
+		TransportSettings: []*internet.TransportConfig{
+			{
+				ProtocolName: t.ProtocolName(),
+				Settings:     s.settings(t),
+			},
+		},
+	}, nil
+}
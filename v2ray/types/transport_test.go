@@ -0,0 +1,201 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/grpc"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/kcp"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/quic"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/websocket"
+
+	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
+)
+
+func TestTransport_String(t *testing.T) {
+	tests := []struct {
+		transport types.Transport
+		name      string
+		protocol  string
+	}{
+		{types.TransportUnspecified, "", ""},
+		{types.TransportTCP, "tcp", "tcp"},
+		{types.TransportMKCP, "mkcp", "mkcp"},
+		{types.TransportWebSocket, "websocket", "websocket"},
+		{types.TransportHTTP, "http", "http"},
+		{types.TransportDomainSocket, "domainsocket", "domainsocket"},
+		{types.TransportQUIC, "quic", "quic"},
+		{types.TransportGUN, "gun", "gun"},
+		{types.TransportGRPC, "grpc", "gun"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.transport.String(); got != tt.name {
+			t.Errorf("Transport(%d).String() = %q, want %q", tt.transport, got, tt.name)
+		}
+		if got := tt.transport.ProtocolName(); got != tt.protocol {
+			t.Errorf("Transport(%d).ProtocolName() = %q, want %q", tt.transport, got, tt.protocol)
+		}
+		if got := types.NewTransportFromString(tt.name); got != tt.transport {
+			t.Errorf("NewTransportFromString(%q) = %d, want %d", tt.name, got, tt.transport)
+		}
+	}
+}
+
+func TestTransport_RequiresTLS(t *testing.T) {
+	tests := []struct {
+		name    string
+		inbound *types.InboundConfig
+		wantErr bool
+	}{
+		{"http without tls", &types.InboundConfig{Port: 443, Proxy: types.ProxyVMess, Transport: types.TransportHTTP}, true},
+		{"quic without tls", &types.InboundConfig{Port: 443, Proxy: types.ProxyVMess, Transport: types.TransportQUIC}, false},
+		{"websocket without tls", &types.InboundConfig{Port: 443, Proxy: types.ProxyVMess, Transport: types.TransportWebSocket}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.inbound.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("InboundConfig.Validate() error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			// The client side of the inbound is held to the same requirement.
+			outbound := &types.OutboundConfig{
+				Address:   "203.0.113.1",
+				Port:      tt.inbound.Port,
+				Proxy:     tt.inbound.Proxy,
+				Transport: tt.inbound.Transport,
+			}
+			if err := outbound.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("OutboundConfig.Validate() error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			outbound.TLS = &types.TLSConfig{ServerName: "node.example.com"}
+			if err := outbound.Validate(); err != nil {
+				t.Fatalf("OutboundConfig.Validate() with tls error = %v", err)
+			}
+		})
+	}
+}
+
+func TestTransportSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport types.Transport
+		settings  *types.TransportSettings
+		wantErr   bool
+	}{
+		{"tcp", types.TransportTCP, &types.TransportSettings{}, false},
+		{"mkcp", types.TransportMKCP, &types.TransportSettings{PacketHeader: types.PacketHeaderUTP}, false},
+		{"mkcp invalid header", types.TransportMKCP, &types.TransportSettings{PacketHeader: 0xFF}, true},
+		{"domainsocket", types.TransportDomainSocket, &types.TransportSettings{Path: "/run/v2ray.sock"}, false},
+		{"domainsocket without path", types.TransportDomainSocket, &types.TransportSettings{}, true},
+		{"quic", types.TransportQUIC, &types.TransportSettings{Security: types.CipherAES128GCM, Key: "key"}, false},
+		{"quic without key", types.TransportQUIC, &types.TransportSettings{Security: types.CipherAES128GCM}, true},
+		{"quic unsupported security", types.TransportQUIC, &types.TransportSettings{Security: types.CipherAES256GCM, Key: "key"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate(tt.transport)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransport_StreamSettings(t *testing.T) {
+	t.Run("invalid transport", func(t *testing.T) {
+		if _, err := types.TransportUnspecified.StreamSettings(nil); err == nil {
+			t.Fatal("StreamSettings() error = nil, want an error")
+		}
+	})
+
+	t.Run("nil settings", func(t *testing.T) {
+		config, err := types.TransportTCP.StreamSettings(nil)
+		if err != nil {
+			t.Fatalf("StreamSettings() error = %v", err)
+		}
+		if config.ProtocolName != "tcp" || len(config.TransportSettings) != 1 {
+			t.Fatalf("StreamSettings() = %v, want tcp settings", config)
+		}
+	})
+
+	t.Run("mkcp", func(t *testing.T) {
+		config, err := types.TransportMKCP.StreamSettings(&types.TransportSettings{Seed: "seed"})
+		if err != nil {
+			t.Fatalf("StreamSettings() error = %v", err)
+		}
+
+		var settings kcp.Config
+		if err := config.TransportSettings[0].Settings.UnmarshalTo(&settings); err != nil {
+			t.Fatalf("UnmarshalTo() error = %v", err)
+		}
+		if settings.GetSeed().GetSeed() != "seed" {
+			t.Errorf("Seed = %q, want %q", settings.GetSeed().GetSeed(), "seed")
+		}
+	})
+
+	t.Run("websocket", func(t *testing.T) {
+		config, err := types.TransportWebSocket.StreamSettings(
+			&types.TransportSettings{
+				Headers: map[string]string{"User-Agent": "test", "Host": "cdn.example.com"},
+				Path:    "/ws",
+			},
+		)
+		if err != nil {
+			t.Fatalf("StreamSettings() error = %v", err)
+		}
+
+		var settings websocket.Config
+		if err := config.TransportSettings[0].Settings.UnmarshalTo(&settings); err != nil {
+			t.Fatalf("UnmarshalTo() error = %v", err)
+		}
+		if settings.Path != "/ws" {
+			t.Errorf("Path = %q, want %q", settings.Path, "/ws")
+		}
+
+		// The headers are sorted by name.
+		if len(settings.Header) != 2 || settings.Header[0].Key != "Host" || settings.Header[1].Key != "User-Agent" {
+			t.Errorf("Header = %v, want Host and User-Agent", settings.Header)
+		}
+	})
+
+	t.Run("quic", func(t *testing.T) {
+		config, err := types.TransportQUIC.StreamSettings(
+			&types.TransportSettings{Key: "key", Security: types.CipherChaCha20Poly1305},
+		)
+		if err != nil {
+			t.Fatalf("StreamSettings() error = %v", err)
+		}
+
+		var settings quic.Config
+		if err := config.TransportSettings[0].Settings.UnmarshalTo(&settings); err != nil {
+			t.Fatalf("UnmarshalTo() error = %v", err)
+		}
+		if settings.Key != "key" || settings.GetSecurity().GetType() != protocol.SecurityType_CHACHA20_POLY1305 {
+			t.Errorf("Config = %v, want the given key and security", &settings)
+		}
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		config, err := types.TransportGRPC.StreamSettings(
+			&types.TransportSettings{Host: []string{"grpc.example.com"}, ServiceName: "tunnel"},
+		)
+		if err != nil {
+			t.Fatalf("StreamSettings() error = %v", err)
+		}
+		if config.ProtocolName != "gun" {
+			t.Errorf("ProtocolName = %q, want %q", config.ProtocolName, "gun")
+		}
+
+		var settings grpc.Config
+		if err := config.TransportSettings[0].Settings.UnmarshalTo(&settings); err != nil {
+			t.Fatalf("UnmarshalTo() error = %v", err)
+		}
+		if settings.Host != "grpc.example.com" || settings.ServiceName != "tunnel" {
+			t.Errorf("Config = %v, want the given host and service name", &settings)
+		}
+	})
+}
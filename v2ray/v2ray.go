@@ -2,167 +2,367 @@ package v2ray
 
 import (
 	"context"
-	"encoding/base64"
 	"errors"
 	"fmt"
-	"os"
-	"os/exec"
+	"io"
 	"path/filepath"
 	"strings"
+	"sync"
 
+	core "github.com/v2fly/v2ray-core/v5"
 	proxymancommand "github.com/v2fly/v2ray-core/v5/app/proxyman/command"
 	statscommand "github.com/v2fly/v2ray-core/v5/app/stats/command"
-	"github.com/v2fly/v2ray-core/v5/common/protocol"
-	"github.com/v2fly/v2ray-core/v5/common/serial"
-	"github.com/v2fly/v2ray-core/v5/common/uuid"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
 	"github.com/sentinel-official/sentinel-go-sdk/v1/v2ray/types"
 )
 
 const (
-	// DataLen represents the expected length of data used for peer operations.
-	DataLen = 1 + 16
+	// ConfigFilename represents the name of the JSON configuration file of the server.
+	//
+	// Deprecated: The server no longer reads or writes this file; its configuration is written to
+	// ProtobufConfigFilename.
+	ConfigFilename = "v2ray_config.json"
 
-	// InfoLen represents the length of the server information.
-	InfoLen = 2 + 1
+	// ProtobufConfigFilename represents the name of the configuration file of the server, in the protobuf format.
+	ProtobufConfigFilename = "v2ray_config.pb"
 
-	// ConfigFilename represents the name of the configuration file.
-	ConfigFilename = "v2ray_config.json"
+	// ClientConfigFilename represents the name of the configuration file of the client.
+	ClientConfigFilename = "v2ray_client_config.pb"
 )
 
 var (
-	_ sentinelsdk.ClientService = (*Client)(nil)
-	_ sentinelsdk.ServerService = (*Server)(nil)
+	_ sentinelsdk.ClientService        = (*Client)(nil)
+	_ sentinelsdk.ServerService        = (*Server)(nil)
+	_ sentinelsdk.ContextServerService = (*Server)(nil)
 )
 
-type Client struct {
+// Server represents the V2Ray server instance.
+type Server struct {
+	mu       sync.Mutex                            // mu serializes changes to the peers and the inbounds of the V2Ray server.
+	applied  map[string]*core.InboundHandlerConfig // applied holds the handler configuration of each inbound of the V2Ray core; protected by mu.
+	config   *ServerConfig                         // config is the configuration of the V2Ray server.
+	draining bool                                  // draining is set while the V2Ray server is draining; protected by mu.
+	homeDir  string                                // homeDir is the home directory of the V2Ray server.
+	levelFn  func(*types.PeerRequest) uint32       // levelFn chooses the policy level of the peers added with AddPeer; protected by mu.
+	peers    *types.Peers                          // peers is a collection of peer information.
+	process  *process                              // process runs the V2Ray core of the server.
+	stop     chan struct{}                         // stop is closed to stop the background routines of the V2Ray server.
+	users    map[string]map[string]bool            // users holds the emails of the users added to each inbound of the V2Ray core; protected by mu.
 }
 
-func (c *Client) Down() error {
-	// TODO implement me
-	panic("implement me")
+// NewServer creates a new instance of the V2Ray server without a configuration.
+// The server cannot be initialized until it is configured; see NewServerWithConfig.
+func NewServer(homeDir string) *Server {
+	return NewServerWithConfig(homeDir, nil)
 }
 
-func (c *Client) Info() []byte {
-	// TODO implement me
-	panic("implement me")
-}
+// NewServerWithConfig creates a new instance of the V2Ray server with the given configuration.
+// The server runs the V2Ray core in the mode given by the configuration.
+func NewServerWithConfig(homeDir string, config *ServerConfig) *Server {
+	s := &Server{
+		applied: make(map[string]*core.InboundHandlerConfig),
+		users:   make(map[string]map[string]bool),
+		config:  config,
+		homeDir: homeDir,
+		peers:   types.NewPeers(),
+	}
 
-func (c *Client) IsUp() bool {
-	// TODO implement me
-	panic("implement me")
+	// Determine the mode from the configuration, defaulting to the exec mode.
+	mode := ModeExec
+	if config != nil && config.Mode.IsEmbedded() {
+		mode = ModeEmbedded
+	}
+
+	s.process = newProcess(mode, s.configFilePath(), APIPort)
+	return s
 }
 
-func (c *Client) PostDown() error {
-	// TODO implement me
-	panic("implement me")
+// WithLevelFunc sets the function choosing the policy level of the peers added with AddPeer,
+// such as from the plan of their subscription, and returns the server. Without it, peers are
+// assigned to level 0. The function is called without holding any lock of the server.
+// A level sets the timeouts, the buffer size and the statistics of its peers, as described by
+// types.PolicyConfig; it cannot limit their connections.
+func (s *Server) WithLevelFunc(fn func(req *types.PeerRequest) uint32) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.levelFn = fn
+	return s
 }
 
-func (c *Client) PostUp() error {
-	// TODO implement me
-	panic("implement me")
+// peerLevel returns the policy level the node chooses for the peer of the given request.
+func (s *Server) peerLevel(req *types.PeerRequest) uint32 {
+	s.mu.Lock()
+	fn := s.levelFn
+	s.mu.Unlock()
+
+	if fn == nil {
+		return 0
+	}
+
+	return fn(req)
 }
 
-func (c *Client) PreDown() error {
-	// TODO implement me
-	panic("implement me")
+// configFilePath returns the full path of the V2Ray server's configuration file.
+func (s *Server) configFilePath() string {
+	return filepath.Join(s.homeDir, ProtobufConfigFilename)
 }
 
-func (c *Client) PreUp() error {
-	// TODO implement me
-	panic("implement me")
+// handlerServiceClient returns a client of the V2Ray server's handler service,
+// along with a closer releasing its resources.
+func (s *Server) handlerServiceClient(ctx context.Context) (io.Closer, proxymancommand.HandlerServiceClient, error) {
+	return s.process.handlerServiceClient(ctx)
 }
 
-func (c *Client) Statistics() (int64, int64, error) {
-	// TODO implement me
-	panic("implement me")
+// statsServiceClient returns a client of the V2Ray server's stats service,
+// along with a closer releasing its resources.
+func (s *Server) statsServiceClient(ctx context.Context) (io.Closer, statscommand.StatsServiceClient, error) {
+	return s.process.statsServiceClient(ctx)
 }
 
-func (c *Client) Up() error {
-	// TODO implement me
-	panic("implement me")
+// decodePeerRequest decodes a peer request meant for the V2Ray service, encoded as a
+// sentinelsdk.PeerRequest with a types.PeerRequest payload.
+func decodePeerRequest(buf []byte) (*types.PeerRequest, error) {
+	// Decode the versioned request, and check that it is meant for the V2Ray service.
+	var envelope sentinelsdk.PeerRequest
+	if err := envelope.UnmarshalBinary(buf); err != nil {
+		return nil, err
+	}
+	if err := envelope.Validate(sentinelsdk.ServiceTypeV2Ray); err != nil {
+		return nil, err
+	}
+
+	// Decode the V2Ray payload of the request.
+	var req types.PeerRequest
+	if err := req.UnmarshalBinary(envelope.Payload); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
 }
 
-// Server represents the V2Ray server instance.
-type Server struct {
-	cmd     *exec.Cmd    // cmd is the command for running the V2Ray server.
-	homeDir string       // homeDir is the home directory of the V2Ray server.
-	info    []byte       // info stores information about the server.
-	peers   *types.Peers // peers is a collection of peer information.
+// peerInbounds returns the inbounds serving the given proxy which are listening on the given ports,
+// or every inbound serving the proxy if no ports are given.
+func (s *Server) peerInbounds(proxy types.Proxy, ports []uint16) ([]*types.InboundConfig, error) {
+	inbounds := s.config.ProxyInbounds(proxy)
+	if len(inbounds) == 0 {
+		return nil, fmt.Errorf("proxy %s is not served", proxy)
+	}
+	if len(ports) == 0 {
+		return inbounds, nil
+	}
+
+	// Look up the inbound listening on each of the given ports.
+	items := make([]*types.InboundConfig, 0, len(ports))
+	for _, port := range ports {
+		var inbound *types.InboundConfig
+		for _, item := range inbounds {
+			if item.Port == port {
+				inbound = item
+			}
+		}
+		if inbound == nil {
+			return nil, fmt.Errorf("proxy %s is not served on port %d", proxy, port)
+		}
+
+		items = append(items, inbound)
+	}
+
+	return items, nil
 }
 
-// NewServer creates a new instance of the V2Ray server.
-func NewServer(homeDir string) *Server {
-	return &Server{
-		cmd:     nil,
-		homeDir: homeDir,
-		info:    make([]byte, InfoLen),
-		peers:   types.NewPeers(),
+// freePort returns the first port of the given inbound which is not assigned to any peer.
+func (s *Server) freePort(inbound *types.InboundConfig) (uint16, error) {
+	// Collect the ports already assigned to peers.
+	used := make(map[uint16]bool)
+	if err := s.peers.Iterate(func(_ string, value *types.Peer) (bool, error) {
+		for _, port := range value.Ports {
+			used[port] = true
+		}
+
+		return false, nil
+	}); err != nil {
+		return 0, err
 	}
+
+	// Return the first port within the range of the inbound that is not in use.
+	first, last := inbound.PortRange()
+	for port := uint32(first); port <= uint32(last); port++ {
+		if !used[uint16(port)] {
+			return uint16(port), nil
+		}
+	}
+
+	return 0, fmt.Errorf("no free port left for inbound %s", inbound.Tag())
 }
 
-// clientConn establishes a gRPC client connection to the V2Ray server.
-func (s *Server) clientConn() (*grpc.ClientConn, error) {
-	// Define the target address for the gRPC client connection.
-	target := "127.0.0.1:23"
+// addPeerToInbound adds the given peer to the given inbound of the V2Ray server.
+// For proxies whose inbounds serve a single user, an inbound dedicated to the peer is created
+// on a free port within the range of the inbound.
+// A user or an inbound left behind in the V2Ray server for the peer is replaced, so the V2Ray server
+// matches the peer information afterwards.
+func (s *Server) addPeerToInbound(ctx context.Context, client proxymancommand.HandlerServiceClient, inbound *types.InboundConfig, peer *types.Peer) error {
+	// Add the user of the peer to the inbound if the inbound serves multiple users.
+	if inbound.Proxy.IsMultiUser() {
+		if err := s.removeUser(ctx, client, inbound.Tag(), peer.Email); err != nil {
+			return err
+		}
+		if err := s.addUser(ctx, client, inbound.Tag(), peer.User(inbound.Cipher)); err != nil {
+			return err
+		}
 
-	// Establish a gRPC client connection with specified options:
-	// - WithBlock: Blocks until the underlying connection is established.
-	// - WithTransportCredentials: Configures insecure transport credentials for the connection.
-	return grpc.Dial(
-		target,
-		grpc.WithBlock(),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+		peer.AddInbound(inbound.Tag(), 0)
+		return nil
+	}
+
+	// Find a port within the range of the inbound that is not assigned to any peer.
+	port, err := s.freePort(inbound)
+	if err != nil {
+		return err
+	}
+
+	// Build the configuration of the inbound dedicated to the peer.
+	config, err := inbound.PeerHandlerConfig(port, peer.User(inbound.Cipher))
+	if err != nil {
+		return err
+	}
+
+	// Add the inbound dedicated to the peer.
+	if err := s.removeInbound(ctx, client, config.Tag); err != nil {
+		return err
+	}
+	if err := s.addInbound(ctx, client, config); err != nil {
+		return err
+	}
+
+	peer.AddInbound(inbound.Tag(), port)
+	return nil
 }
 
-// configFilePath returns the full path of the V2Ray server's configuration file.
-func (s *Server) configFilePath() string {
-	return filepath.Join(s.homeDir, ConfigFilename)
+// removePeerFromInbound removes the given peer from the given inbound of the V2Ray server.
+// For proxies whose inbounds serve a single user, the inbound dedicated to the peer is removed.
+func (s *Server) removePeerFromInbound(ctx context.Context, client proxymancommand.HandlerServiceClient, inbound *types.InboundConfig, peer *types.Peer) error {
+	if inbound.Proxy.IsMultiUser() {
+		// Remove the user of the peer from the inbound.
+		if err := s.removeUser(ctx, client, inbound.Tag(), peer.Email); err != nil {
+			return err
+		}
+	} else {
+		// Remove the inbound dedicated to the peer.
+		if err := s.removeInbound(ctx, client, inbound.PeerTag(peer.Email)); err != nil {
+			return err
+		}
+	}
+
+	peer.RemoveInbound(inbound.Tag())
+	return nil
 }
 
-// handlerServiceClient establishes a gRPC client connection to the V2Ray server's handler service.
-func (s *Server) handlerServiceClient() (*grpc.ClientConn, proxymancommand.HandlerServiceClient, error) {
-	// Establish a gRPC client connection using the clientConn method.
-	conn, err := s.clientConn()
+// peerResponse encodes the response to a request adding the given peer, as a sentinelsdk.PeerResponse
+// with a types.PeerResponse payload listing the inbounds serving the peer.
+func (s *Server) peerResponse(peer *types.Peer) ([]byte, error) {
+	var res types.PeerResponse
+	for _, tag := range peer.Inbounds {
+		inbound := s.config.Inbound(tag)
+		if inbound == nil || inbound.Transport == types.TransportDomainSocket {
+			continue
+		}
+
+		// Replace the range of ports of the inbound with the port of the inbound dedicated to the peer, if any.
+		info := inbound.Info()
+		if port, ok := peer.Ports[tag]; ok {
+			info.Port, info.Ports = port, 0
+		}
+
+		res.Inbounds = append(res.Inbounds, info)
+	}
+
+	// Encode the V2Ray payload of the response.
+	payload, err := res.MarshalBinary()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	// Create a new HandlerServiceClient using the established connection.
-	client := proxymancommand.NewHandlerServiceClient(conn)
+	// Encode the versioned response.
+	envelope := &sentinelsdk.PeerResponse{
+		Type:    sentinelsdk.ServiceTypeV2Ray,
+		Payload: payload,
+	}
 
-	// Return both the connection and the client.
-	return conn, client, nil
+	return envelope.MarshalBinary()
 }
 
-// statsServiceClient establishes a gRPC client connection to the V2Ray server's stats service.
-func (s *Server) statsServiceClient() (*grpc.ClientConn, statscommand.StatsServiceClient, error) {
-	// Establish a gRPC client connection using the clientConn method.
-	conn, err := s.clientConn()
+// AddPeer adds a new peer to the V2Ray server, assigned to the policy level chosen by the function
+// set with WithLevelFunc, or to level 0. Clients cannot choose their own level.
+// See AddPeerWithLevel for the request and the response.
+func (s *Server) AddPeer(ctx context.Context, buf []byte) ([]byte, error) {
+	// Decode the peer request.
+	req, err := decodePeerRequest(buf)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	// Create a new StatsServiceClient using the established connection.
-	client := statscommand.NewStatsServiceClient(conn)
+	return s.addPeer(ctx, req, s.peerLevel(req))
+}
+
+// AddPeerWithLevel adds a new peer to the V2Ray server, assigned to the given policy level.
+//
+// The request is a sentinelsdk.PeerRequest with a types.PeerRequest payload. The peer is served by every
+// inbound of its proxy type, unless the request lists the ports of the inbounds chosen to serve it.
+// For proxies whose inbounds serve a single user, such as Shadowsocks, an inbound dedicated to the peer
+// is created on each chosen inbound.
+//
+// The response is a sentinelsdk.PeerResponse with a types.PeerResponse payload, listing the inbounds
+// serving the peer along with the ports assigned to it.
+// Adding a peer which already exists succeeds without any change and returns the same response.
+// If adding the peer to one of the inbounds fails, it is removed from the others, and nothing changes.
+func (s *Server) AddPeerWithLevel(ctx context.Context, buf []byte, level uint32) ([]byte, error) {
+	// Decode the peer request.
+	req, err := decodePeerRequest(buf)
+	if err != nil {
+		return nil, err
+	}
 
-	// Return both the connection and the client.
-	return conn, client, nil
+	return s.addPeer(ctx, req, level)
 }
 
-// AddPeer adds a new peer to the V2Ray server.
-func (s *Server) AddPeer(ctx context.Context, buf []byte) ([]byte, error) {
-	// Check if the data length is valid.
-	if len(buf) != DataLen {
-		return nil, fmt.Errorf("invalid data length; expected %d, got %d", DataLen, len(buf))
+// addPeer adds a new peer to the V2Ray server as described by the given request,
+// assigned to the given policy level.
+func (s *Server) addPeer(ctx context.Context, req *types.PeerRequest, level uint32) ([]byte, error) {
+	// Check if the configuration is nil.
+	if s.config == nil {
+		return nil, errors.New("nil config")
+	}
+
+	// Serialize the change, so concurrent peers are not assigned the same port
+	// and inbounds are not reloaded while the peer is added.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Check if the policy level is configured.
+	if !s.config.HasLevel(level) {
+		return nil, fmt.Errorf("policy level %d does not exist", level)
+	}
+
+	// Return the response for the peer if it already exists.
+	if peer := s.peers.Get(req.Email()); peer != nil {
+		return s.peerResponse(peer)
+	}
+
+	// Reject new peers while the server is draining.
+	if s.draining {
+		return nil, sentinelsdk.ErrDraining
+	}
+
+	// Look up the inbounds chosen to serve the peer.
+	inbounds, err := s.peerInbounds(req.Proxy, req.Ports)
+	if err != nil {
+		return nil, err
 	}
 
 	// Establish a gRPC client connection to the handler service.
-	conn, client, err := s.handlerServiceClient()
+	conn, client, err := s.handlerServiceClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -174,77 +374,92 @@ func (s *Server) AddPeer(ctx context.Context, buf []byte) ([]byte, error) {
 		}
 	}()
 
-	// Encode the data buffer to email using base64 encoding and extract proxy type.
-	var (
-		email = base64.StdEncoding.EncodeToString(buf)
-		proxy = types.Proxy(buf[0])
-	)
-
-	// Parse the UUID from the data buffer.
-	uid, err := uuid.ParseBytes(buf[1:])
-	if err != nil {
-		return nil, err
+	// Prepare the peer information.
+	peer := &types.Peer{
+		Email:  req.Email(),
+		Level:  level,
+		Pinned: len(req.Ports) > 0,
+		Proxy:  req.Proxy,
+		UID:    req.UID,
 	}
 
-	// Prepare gRPC request to add a user to the handler.
-	req := &proxymancommand.AlterInboundRequest{
-		Tag: proxy.Tag(),
-		Operation: serial.ToTypedMessage(
-			&proxymancommand.AddUserOperation{
-				User: &protocol.User{
-					Level:   0,
-					Email:   email,
-					Account: proxy.Account(uid),
-				},
-			},
-		),
-	}
+	// Add the peer to each inbound, undoing the additions if one of them fails.
+	for i, inbound := range inbounds {
+		if err := s.addPeerToInbound(ctx, client, inbound, peer); err != nil {
+			for _, item := range inbounds[:i] {
+				_ = s.removePeerFromInbound(ctx, client, item, peer)
+			}
 
-	// Send the request to add a user to the handler.
-	_, err = client.AlterInbound(ctx, req)
-	if err != nil {
-		return nil, err
+			return nil, err
+		}
 	}
 
 	// Update the local peer collection with the new peer information.
-	s.peers.Put(
-		&types.Peer{
-			Email: email,
-		},
-	)
+	s.peers.Put(peer)
 
-	// Return nil for success (no additional data to return in response).
-	return nil, nil
+	// Return the inbounds serving the peer.
+	return s.peerResponse(peer)
 }
 
 // HasPeer checks if a peer exists in the V2Ray server's peer list.
+// The request is encoded as for AddPeer; only its proxy and UUID are used.
 func (s *Server) HasPeer(_ context.Context, buf []byte) (bool, error) {
-	// Check if the data length is valid.
-	if len(buf) != DataLen {
-		return false, fmt.Errorf("invalid data length; expected %d, got %d", DataLen, len(buf))
+	// Decode the peer request.
+	req, err := decodePeerRequest(buf)
+	if err != nil {
+		return false, err
 	}
 
-	// Encode the data buffer to email using base64 encoding.
-	var (
-		email = base64.StdEncoding.EncodeToString(buf)
-		peer  = s.peers.Get(email)
-	)
-
 	// Return true if the peer exists, otherwise false.
-	return peer != nil, nil
+	return s.peers.Get(req.Email()) != nil, nil
 }
 
-// Info returns information about the V2Ray server.
+// Info returns information about the V2Ray server, encoded in the binary format of types.Info.
+// It returns nil if the server is not configured.
 func (s *Server) Info() []byte {
-	return s.info
+	// Check if the configuration is nil.
+	if s.config == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Encode the information about the inbounds served to peers.
+	buf, err := s.config.Info().MarshalBinary()
+	if err != nil {
+		return nil
+	}
+
+	return buf
 }
 
-// Init initializes the V2Ray server.
+// Init initializes the V2Ray server by building its core configuration.
+// In exec mode, the configuration is written to the configuration file.
 func (s *Server) Init() error {
-	// TODO: Initialization logic.
-	// ...
+	return s.InitContext(context.Background())
+}
 
-	return nil
+// InitContext initializes the V2Ray server as Init does, returning the error of the context if it is done.
+func (s *Server) InitContext(ctx context.Context) error {
+	// Check if the context is done.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Check if the configuration is nil.
+	if s.config == nil {
+		return errors.New("nil config")
+	}
+
+	// Build the V2Ray core configuration from the server configuration.
+	config, err := s.config.Build()
+	if err != nil {
+		return err
+	}
+
+	// Prepare the process to run the configuration.
+	return s.process.init(config)
 }
 
 // PeerCount returns the number of peers connected to the V2Ray server.
@@ -255,7 +470,7 @@ func (s *Server) PeerCount() int {
 // PeerStatistics retrieves statistics for each peer connected to the V2Ray server.
 func (s *Server) PeerStatistics(ctx context.Context) (items []*sentinelsdk.PeerStatistic, err error) {
 	// Establish a gRPC client connection to the stats service.
-	conn, client, err := s.statsServiceClient()
+	conn, client, err := s.statsServiceClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -334,14 +549,34 @@ func (s *Server) PeerStatistics(ctx context.Context) (items []*sentinelsdk.PeerS
 }
 
 // RemovePeer removes a peer from the V2Ray server.
+// The request is encoded as for AddPeer; the peer is removed from every inbound serving it,
+// regardless of the ports listed in the request.
+// Removing a peer which does not exist succeeds, after removing any user or inbound left behind
+// in the V2Ray server for it. If removing the peer from one of the inbounds fails, the peer is kept
+// with the inbounds it could not be removed from, so the removal can be retried.
 func (s *Server) RemovePeer(ctx context.Context, buf []byte) error {
-	// Check if the data length is valid.
-	if len(buf) != DataLen {
-		return fmt.Errorf("invalid data length; expected %d, got %d", DataLen, len(buf))
+	// Decode the peer request.
+	req, err := decodePeerRequest(buf)
+	if err != nil {
+		return err
+	}
+
+	var (
+		email = req.Email()
+		proxy = req.Proxy
+	)
+
+	// Check if the configuration is nil.
+	if s.config == nil {
+		return errors.New("nil config")
 	}
 
+	// Serialize the change, so inbounds are not reloaded while the peer is removed.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	// Establish a gRPC client connection to the handler service.
-	conn, client, err := s.handlerServiceClient()
+	conn, client, err := s.handlerServiceClient(ctx)
 	if err != nil {
 		return err
 	}
@@ -353,27 +588,26 @@ func (s *Server) RemovePeer(ctx context.Context, buf []byte) error {
 		}
 	}()
 
-	// Encode the data buffer to email using base64 encoding and extract proxy type.
-	var (
-		email = base64.StdEncoding.EncodeToString(buf)
-		proxy = types.Proxy(buf[0])
-	)
+	// Remove a peer unknown to the local collection from every inbound of its proxy type.
+	peer := s.peers.Get(email)
+	if peer == nil {
+		peer = &types.Peer{
+			Email: email,
+			Proxy: proxy,
+		}
 
-	// Prepare gRPC request to remove a user from the handler.
-	req := &proxymancommand.AlterInboundRequest{
-		Tag: proxy.Tag(),
-		Operation: serial.ToTypedMessage(
-			&proxymancommand.RemoveUserOperation{
-				Email: email,
-			},
-		),
+		for _, inbound := range s.config.ProxyInbounds(proxy) {
+			peer.AddInbound(inbound.Tag(), 0)
+		}
 	}
 
-	// Send the request to remove a user from the handler.
-	_, err = client.AlterInbound(ctx, req)
-	if err != nil {
-		// If the user is not found, continue without error.
-		if !strings.Contains(err.Error(), "not found") {
+	// Remove the peer from each inbound serving it.
+	for _, inbound := range s.config.ProxyInbounds(proxy) {
+		if !peer.HasInbound(inbound.Tag()) {
+			continue
+		}
+
+		if err := s.removePeerFromInbound(ctx, client, inbound, peer); err != nil {
 			return err
 		}
 	}
@@ -385,38 +619,102 @@ func (s *Server) RemovePeer(ctx context.Context, buf []byte) error {
 	return nil
 }
 
-// Start starts the V2Ray server.
+// Start starts the V2Ray server. It does not wait for the V2Ray API to be ready; see WaitReady.
 func (s *Server) Start() error {
-	// Create a new command to execute the V2Ray binary.
-	s.cmd = exec.Command(
-		s.execFile(), // Get the path to the V2Ray binary.
-		strings.Split(
-			fmt.Sprintf("run --config %s", s.configFilePath()), // Construct the command-line arguments.
-			" ",
-		)...,
-	)
+	return s.StartContext(context.Background())
+}
+
+// StartContext starts the V2Ray server as Start does, returning the error of the context if it is done.
+func (s *Server) StartContext(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Start the V2Ray core.
+	if err := s.process.start(ctx); err != nil {
+		return err
+	}
 
-	// Redirect standard output and error streams to the console.
-	s.cmd.Stdout = os.Stdout
-	s.cmd.Stderr = os.Stderr
+	// The V2Ray core starts with the inbounds of its configuration and no users added.
+	s.resetApplied(s.process.coreConfig())
 
-	// Start the V2Ray server by executing the command.
-	return s.cmd.Start()
+	// Accept new peers again after a previous drain.
+	s.draining = false
+
+	// Start watching the TLS certificates for changes on disk.
+	s.stop = make(chan struct{})
+	go s.watchCertificates(s.stop)
+
+	return nil
+}
+
+// resetApplied records the inbounds of the given V2Ray core configuration as the only ones applied,
+// without any users added. The caller must hold mu.
+func (s *Server) resetApplied(config *core.Config) {
+	s.applied = make(map[string]*core.InboundHandlerConfig)
+	s.users = make(map[string]map[string]bool)
+
+	if config == nil {
+		return
+	}
+
+	for _, item := range config.Inbound {
+		s.applied[item.Tag] = item
+	}
+}
+
+// Status returns the state of the V2Ray server, probing whether the V2Ray API responds to requests.
+func (s *Server) Status(ctx context.Context) *sentinelsdk.ServiceStatus {
+	status := &sentinelsdk.ServiceStatus{
+		Draining: s.isDraining(),
+		PID:      s.process.pid(),
+		Peers:    s.PeerCount(),
+		Running:  s.process.isRunning(),
+		Uptime:   s.process.uptime(),
+	}
+
+	// Report how many times the V2Ray core was restarted, and how many times it exited on its own.
+	status.Restarts, status.Crashes = s.process.counters()
+
+	// Probe the V2Ray API only if the V2Ray core is running.
+	if status.Running {
+		status.APIReachable = s.process.isReady(ctx)
+	}
+
+	// Report the last error of the V2Ray core, if any.
+	if err := s.process.getLastErr(); err != nil {
+		status.LastError = err.Error()
+	}
+
+	return status
 }
 
-// Stop stops the V2Ray server.
+// Stop stops the V2Ray server, waiting for the V2Ray core to exit.
 func (s *Server) Stop() error {
-	// Check if the command is nil.
-	if s.cmd == nil {
-		// If the command is nil, return an error indicating that the command is not initialized.
-		return errors.New("nil cmd")
+	return s.StopContext(context.Background())
+}
+
+// StopContext stops the V2Ray server as Stop does. In exec mode, the V2Ray binary is asked to terminate,
+// and killed if it does not exit within StopTimeout or before the context is done.
+func (s *Server) StopContext(ctx context.Context) error {
+	// Stop watching the TLS certificates.
+	s.mu.Lock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
 	}
+	s.mu.Unlock()
 
-	// Kill the process associated with the command to stop the V2Ray server.
-	return s.cmd.Process.Kill()
+	// Stop the V2Ray core.
+	return s.process.stop(ctx)
 }
 
 // Type returns the service type of the V2Ray server.
 func (s *Server) Type() sentinelsdk.ServiceType {
 	return sentinelsdk.ServiceTypeV2Ray
 }
+
+// WaitReady blocks until the V2Ray API of the server responds to requests, so peers can be added
+// right after Start. It returns an error if the V2Ray core stops or the context is done first.
+func (s *Server) WaitReady(ctx context.Context) error {
+	return s.process.waitReady(ctx)
+}
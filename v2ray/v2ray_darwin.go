@@ -1,6 +1,6 @@
 package v2ray
 
-// execFile returns the name of the executable file for the V2Ray server.
-func (s *Server) execFile() string {
+// execFile returns the name of the executable file for the V2Ray binary.
+func execFile() string {
 	return "v2ray"
 }
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+)
+
+// KeyLen represents the length of a WireGuard public key.
+const KeyLen = 32
+
+// PeerRequest represents the WireGuard payload of a request to add, check or remove a peer.
+//
+// It is laid out as follows:
+//
+//	public key (32 bytes)
+//
+// Decoders skip the bytes appended by later revisions.
+type PeerRequest struct {
+	PublicKey [KeyLen]byte // PublicKey is the public key of the peer.
+}
+
+// Key returns the base64 encoded public key identifying the peer.
+func (r *PeerRequest) Key() string {
+	return base64.StdEncoding.EncodeToString(r.PublicKey[:])
+}
+
+// MarshalBinary encodes the PeerRequest in the binary format.
+func (r *PeerRequest) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), r.PublicKey[:]...), nil
+}
+
+// UnmarshalBinary decodes the PeerRequest from the binary format.
+func (r *PeerRequest) UnmarshalBinary(buf []byte) error {
+	if len(buf) < KeyLen {
+		return fmt.Errorf("%w: invalid peer request length; expected at least %d, got %d", sentinelsdk.ErrInvalidPayload, KeyLen, len(buf))
+	}
+
+	copy(r.PublicKey[:], buf[:KeyLen])
+	return nil
+}
+
+// PeerResponse represents the WireGuard payload of the response to a request adding a peer.
+//
+// It is laid out as follows, with multi-byte integers in big-endian byte order:
+//
+//	public key (32 bytes) | port (2 bytes) | count (1 byte) | count addresses
+//
+// and each address is laid out as:
+//
+//	length (1 byte) | ip (4 or 16 bytes) | prefix length (1 byte)
+//
+// where length is the length of the IP address. Decoders skip the bytes appended by later revisions.
+type PeerResponse struct {
+	PublicKey [KeyLen]byte   // PublicKey is the public key of the server.
+	Port      uint16         // Port is the port the server listens on.
+	Addrs     []netip.Prefix // Addrs is the list of addresses assigned to the peer.
+}
+
+// MarshalBinary encodes the PeerResponse in the binary format.
+func (r *PeerResponse) MarshalBinary() ([]byte, error) {
+	if len(r.Addrs) > 255 {
+		return nil, fmt.Errorf("too many addresses; expected at most 255, got %d", len(r.Addrs))
+	}
+
+	buf := append([]byte(nil), r.PublicKey[:]...)
+	buf = binary.BigEndian.AppendUint16(buf, r.Port)
+	buf = append(buf, byte(len(r.Addrs)))
+
+	for _, addr := range r.Addrs {
+		ip := addr.Addr().AsSlice()
+		buf = append(buf, byte(len(ip)))
+		buf = append(buf, ip...)
+		buf = append(buf, byte(addr.Bits()))
+	}
+
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the PeerResponse from the binary format.
+func (r *PeerResponse) UnmarshalBinary(buf []byte) error {
+	if len(buf) < KeyLen+2+1 {
+		return fmt.Errorf("%w: invalid peer response length; expected at least %d, got %d", sentinelsdk.ErrInvalidPayload, KeyLen+2+1, len(buf))
+	}
+
+	var res PeerResponse
+	copy(res.PublicKey[:], buf[:KeyLen])
+	res.Port = binary.BigEndian.Uint16(buf[KeyLen:])
+
+	count := int(buf[KeyLen+2])
+	buf = buf[KeyLen+2+1:]
+
+	for i := 0; i < count; i++ {
+		// Read the address, along with its length and its prefix length.
+		if len(buf) < 1 || len(buf) < 1+int(buf[0])+1 {
+			return fmt.Errorf("%w: unexpected end of peer response", sentinelsdk.ErrInvalidPayload)
+		}
+
+		length := int(buf[0])
+		ip, ok := netip.AddrFromSlice(buf[1 : 1+length])
+		if !ok {
+			return fmt.Errorf("%w: invalid ip address length %d", sentinelsdk.ErrInvalidPayload, length)
+		}
+
+		// Keep the host bits of the address, which identify the peer within the network.
+		prefix := netip.PrefixFrom(ip, int(buf[1+length]))
+		if !prefix.IsValid() {
+			return fmt.Errorf("%w: invalid prefix length %d", sentinelsdk.ErrInvalidPayload, buf[1+length])
+		}
+
+		res.Addrs = append(res.Addrs, prefix)
+		buf = buf[1+length+1:]
+	}
+
+	*r = res
+	return nil
+}
@@ -0,0 +1,172 @@
+package types_test
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	sentinelsdk "github.com/sentinel-official/sentinel-go-sdk/v1/types"
+	"github.com/sentinel-official/sentinel-go-sdk/v1/wireguard/types"
+)
+
+// key returns a public key filled with the given byte.
+func key(b byte) (v [types.KeyLen]byte) {
+	for i := range v {
+		v[i] = b
+	}
+
+	return v
+}
+
+func TestPeerRequest_MarshalBinary(t *testing.T) {
+	req := &types.PeerRequest{PublicKey: key(0x01)}
+
+	buf, err := req.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+	if len(buf) != types.KeyLen {
+		t.Fatalf("MarshalBinary() length = %d, want %d", len(buf), types.KeyLen)
+	}
+
+	var got types.PeerRequest
+	if err := got.UnmarshalBinary(buf); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	if got != *req {
+		t.Fatalf("UnmarshalBinary() = %v, want %v", got, *req)
+	}
+	if got.Key() != "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=" {
+		t.Fatalf("Key() = %q", got.Key())
+	}
+}
+
+func TestPeerRequest_UnmarshalBinary(t *testing.T) {
+	full := key(0x02)
+
+	tests := []struct {
+		name    string
+		buf     []byte
+		want    types.PeerRequest
+		wantErr bool
+	}{
+		{"empty", nil, types.PeerRequest{}, true},
+		{"truncated", full[:types.KeyLen-1], types.PeerRequest{}, true},
+		{"exact", full[:], types.PeerRequest{PublicKey: full}, false},
+		{"later revision", append(full[:], 0xFF, 0xFF), types.PeerRequest{PublicKey: full}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got types.PeerRequest
+
+			err := got.UnmarshalBinary(tt.buf)
+			if tt.wantErr {
+				if !errors.Is(err, sentinelsdk.ErrInvalidPayload) {
+					t.Fatalf("UnmarshalBinary() error = %v, want %v", err, sentinelsdk.ErrInvalidPayload)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("UnmarshalBinary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeerResponse_MarshalBinary(t *testing.T) {
+	tests := []struct {
+		name string
+		res  *types.PeerResponse
+	}{
+		{
+			name: "no addresses",
+			res:  &types.PeerResponse{PublicKey: key(0x03), Port: 51820},
+		},
+		{
+			name: "ipv4 and ipv6",
+			res: &types.PeerResponse{
+				PublicKey: key(0x04),
+				Port:      51820,
+				Addrs: []netip.Prefix{
+					netip.MustParsePrefix("10.8.0.2/24"),
+					netip.MustParsePrefix("fd00::2/64"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := tt.res.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+
+			var got types.PeerResponse
+			if err := got.UnmarshalBinary(buf); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if !reflect.DeepEqual(&got, tt.res) {
+				t.Fatalf("UnmarshalBinary() = %v, want %v", &got, tt.res)
+			}
+		})
+	}
+}
+
+func TestPeerResponse_UnmarshalBinary(t *testing.T) {
+	valid, err := (&types.PeerResponse{
+		PublicKey: key(0x05),
+		Port:      51820,
+		Addrs:     []netip.Prefix{netip.MustParsePrefix("10.8.0.2/24")},
+	}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	// Replace the length of the address with an invalid length.
+	badLength := append([]byte{}, valid...)
+	badLength[types.KeyLen+3] = 3
+
+	// Replace the prefix length of the address with one longer than the address.
+	badPrefix := append([]byte{}, valid...)
+	badPrefix[len(badPrefix)-1] = 33
+
+	tests := []struct {
+		name    string
+		buf     []byte
+		wantErr bool
+	}{
+		{"empty", nil, true},
+		{"truncated header", valid[:types.KeyLen+2], true},
+		{"truncated address", valid[:len(valid)-1], true},
+		{"invalid address length", badLength, true},
+		{"invalid prefix length", badPrefix, true},
+		{"valid", valid, false},
+		{"later revision", append(append([]byte{}, valid...), 0xFF), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got types.PeerResponse
+
+			err := got.UnmarshalBinary(tt.buf)
+			if tt.wantErr {
+				if !errors.Is(err, sentinelsdk.ErrInvalidPayload) {
+					t.Fatalf("UnmarshalBinary() error = %v, want %v", err, sentinelsdk.ErrInvalidPayload)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if got.Port != 51820 || len(got.Addrs) != 1 {
+				t.Fatalf("UnmarshalBinary() = %v", &got)
+			}
+		})
+	}
+}